@@ -3,6 +3,23 @@
 
 package config
 
+import "time"
+
 type DataplaneResourceData struct {
 	Version string
+
+	HTTPProxy  string
+	HTTPSProxy string
+	NoProxy    string
+
+	AwsMaxAttempts    int
+	AwsRequestTimeout time.Duration
+
+	KubeMaxRetries int
+	KubeMaxBackoff time.Duration
+	KubeQPS        float64
+	KubeBurst      int
+
+	DefaultTags map[string]string
+	DryRun      bool
 }
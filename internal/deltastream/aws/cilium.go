@@ -9,6 +9,7 @@ import (
 	_ "embed"
 	"fmt"
 	"html/template"
+	"io"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -30,10 +31,12 @@ var ciliumChart []byte
 //go:embed assets/cilium-values.yaml.tmpl
 var ciliumValuesTemplate string
 
-func installCilium(ctx context.Context, cfg aws.Config, dp awsconfig.AWSDataplane) (d diag.Diagnostics) {
-	kubeConfig, err := util.GetKubeConfig(ctx, dp, cfg)
-	if err != nil {
-		d.AddError("error getting kubeconfig", err.Error())
+// installCilium installs the Cilium release and waits for the nodes to become ready. Unless installOnly is set an
+// existing release is upgraded when the chart version or values changed.
+func installCilium(ctx context.Context, settings util.ClientSettings, cfg aws.Config, dp awsconfig.AWSDataplane, getKubeClient kubeClientFactory, installOnly bool) (d diag.Diagnostics) {
+	kubeConfig, diags := util.GetKubeConfig(ctx, settings, dp, cfg)
+	d.Append(diags...)
+	if d.HasError() {
 		return
 	}
 
@@ -43,9 +46,9 @@ func installCilium(ctx context.Context, cfg aws.Config, dp awsconfig.AWSDataplan
 		return
 	}
 
-	clusterName, err := util.GetKubeClusterName(ctx, dp)
-	if err != nil {
-		d.AddError("error getting cluster name", err.Error())
+	clusterName, diags := util.GetKubeClusterName(ctx, dp)
+	d.Append(diags...)
+	if d.HasError() {
 		return
 	}
 
@@ -59,58 +62,85 @@ func installCilium(ctx context.Context, cfg aws.Config, dp awsconfig.AWSDataplan
 		"ClusterName":     clusterName,
 		"EcrAwsAccountId": config.AccountId.ValueString(),
 		"Region":          cfg.Region,
+		"AwsCniChaining":  config.NetworkMode.ValueString() == awsconfig.NetworkModeAwsCniChaining,
 	}); err != nil {
 		d.AddError("error executing cilium values template", err.Error())
 		return
 	}
 
-	if err = helm.InstallRelease(ctx, kubeConfig, "kube-system", "cilium", bytes.NewBuffer(ciliumChart), b.Bytes(), true); err != nil {
+	var chart io.Reader = bytes.NewBuffer(ciliumChart)
+	if !(config.CiliumVersion.IsNull() || config.CiliumVersion.IsUnknown()) {
+		chart, err = helm.DownloadChart(ctx, config.CiliumChartRepository.ValueString(), "cilium", config.CiliumVersion.ValueString())
+		if err != nil {
+			d.AddError("error downloading cilium chart", err.Error())
+			return
+		}
+	}
+
+	installTimeout, err := time.ParseDuration(config.CiliumInstallTimeout.ValueString())
+	if err != nil {
+		d.AddError("invalid cilium install timeout", err.Error())
+		return
+	}
+
+	if err = helm.InstallRelease(ctx, kubeConfig, config.Namespaces().KubeSystem, "cilium", chart, b.Bytes(), installOnly, installTimeout); err != nil {
 		d.AddError("error installing cilium release", err.Error())
 		return
 	}
 
+	nodesReadyTimeout, err := time.ParseDuration(config.CiliumNodesReadyTimeout.ValueString())
+	if err != nil {
+		d.AddError("invalid cilium nodes ready timeout", err.Error())
+		return
+	}
+
 	tflog.Debug(ctx, "cilium installed, wait for nodes to be ready")
-	err = retry.Do(ctx, retry.WithMaxDuration(time.Minute*5, retry.NewConstant(time.Second*5)), func(ctx context.Context) error {
-		kubeClient, err := util.GetKubeClient(ctx, cfg, dp)
-		if err != nil {
-			return retry.RetryableError(err)
+	readyNodes, totalNodes := 0, 0
+	err = retry.Do(ctx, retry.WithMaxDuration(nodesReadyTimeout, retry.NewConstant(time.Second*5)), func(ctx context.Context) error {
+		kubeClient, diags := getKubeClient(ctx, cfg, dp)
+		if diags.HasError() {
+			return retry.RetryableError(util.DiagnosticsError(diags))
 		}
 
 		nodes := corev1.NodeList{}
-		if err = kubeClient.List(ctx, &nodes); err != nil {
+		if err := kubeClient.List(ctx, &nodes); err != nil {
 			return retry.RetryableError(err)
 		}
 
-		ready := true
+		readyNodes, totalNodes = 0, len(nodes.Items)
 		for _, node := range nodes.Items {
 			for _, c := range node.Status.Conditions {
-				if c.Type == corev1.NodeReady && c.Status != corev1.ConditionTrue {
-					ready = false
-					break
+				if c.Type != corev1.NodeReady {
+					continue
+				}
+				tflog.Debug(ctx, "node readiness", map[string]any{"node": node.Name, "status": c.Status, "reason": c.Reason, "message": c.Message})
+				if c.Status == corev1.ConditionTrue {
+					readyNodes++
 				}
+				break
 			}
 		}
 
-		if !ready {
-			return retry.RetryableError(fmt.Errorf("nodes not ready"))
+		if readyNodes != totalNodes {
+			return retry.RetryableError(fmt.Errorf("%d of %d nodes ready", readyNodes, totalNodes))
 		}
 		return nil
 	})
 	if err != nil {
-		d.AddError("timeout waiting for nodes to be ready", err.Error())
+		d.AddError(fmt.Sprintf("timeout waiting for nodes to be ready (%d of %d nodes ready after %s)", readyNodes, totalNodes, nodesReadyTimeout), err.Error())
 		return
 	}
 	tflog.Debug(ctx, "nodes are ready")
 
-	kubeClient, err := util.GetKubeClient(ctx, cfg, dp)
-	if err != nil {
-		d.AddError("error getting kube client", err.Error())
+	kubeClient, diags := getKubeClient(ctx, cfg, dp)
+	d.Append(diags...)
+	if d.HasError() {
 		return
 	}
 
 	tflog.Debug(ctx, "restarting kube-system deployments")
 	deployments := appsv1.DeploymentList{}
-	if err := kubeClient.List(ctx, &deployments, client.InNamespace("kube-system")); err != nil {
+	if err := kubeClient.List(ctx, &deployments, client.InNamespace(config.Namespaces().KubeSystem)); err != nil {
 		d.AddError("error listing kube-system deployments", err.Error())
 		return
 	}
@@ -119,7 +149,7 @@ func installCilium(ctx context.Context, cfg aws.Config, dp awsconfig.AWSDataplan
 		if deployment.Spec.Template.Annotations == nil {
 			deployment.Spec.Template.Annotations = map[string]string{}
 		}
-		deployment.Spec.Template.Annotations["io.deltastream.tf-deltastream/restartedAt"] = time.Now().Format(time.RFC3339)
+		deployment.Spec.Template.Annotations[restartedAtAnnotation] = time.Now().Format(time.RFC3339)
 		if err := retry.RetryableError(kubeClient.Update(ctx, &deployment)); err != nil {
 			d.AddError("error updating deployment "+deployment.Name, err.Error())
 			return
@@ -4,43 +4,64 @@
 package aws
 
 import (
+	"bytes"
 	"context"
+	"fmt"
 	"net/url"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	awsconfig "github.com/deltastreaminc/terraform-provider-dataplane/internal/deltastream/aws/config"
 	"github.com/deltastreaminc/terraform-provider-dataplane/internal/deltastream/aws/util"
 )
 
-func updateClusterConfig(ctx context.Context, cfg aws.Config, dp awsconfig.AWSDataplane, infraVersion string) (d diag.Diagnostics) {
-	kubeClient, err := util.GetKubeClient(ctx, cfg, dp)
-	if err != nil {
-		d.AddError("error getting kube client", err.Error())
+func updateClusterConfig(ctx context.Context, cfg aws.Config, dp awsconfig.AWSDataplane, getKubeClient kubeClientFactory, infraVersion string) (d diag.Diagnostics) {
+	kubeClient, diags := getKubeClient(ctx, cfg, dp)
+	d.Append(diags...)
+	if d.HasError() {
 		return
 	}
 
-	ns := &corev1.Namespace{ObjectMeta: v1.ObjectMeta{Name: "cluster-config"}}
-	controllerutil.CreateOrUpdate(ctx, kubeClient.Client, ns, func() error {
-		return nil
-	})
-
 	config, diags := dp.ClusterConfigurationData(ctx)
 	d.Append(diags...)
 	if d.HasError() {
 		return
 	}
+	namespaces := config.Namespaces()
 
-	cluster, err := util.DescribeKubeCluster(ctx, dp, cfg)
-	if err != nil {
-		d.AddError("error getting cluster", err.Error())
+	observability, diags := config.ObservabilityData(ctx)
+	d.Append(diags...)
+	if d.HasError() {
+		return
+	}
+
+	if !kubeClient.DryRun() {
+		ns := &corev1.Namespace{ObjectMeta: v1.ObjectMeta{Name: namespaces.ClusterConfig}}
+		controllerutil.CreateOrUpdate(ctx, kubeClient.Client, ns, func() error {
+			return nil
+		})
+	}
+
+	cluster, diags := util.DescribeKubeCluster(ctx, dp, cfg)
+	d.Append(diags...)
+	if d.HasError() {
+		return
+	}
+	oidcIssuer, diags := util.OidcIssuer(cluster)
+	d.Append(diags...)
+	if d.HasError() {
 		return
 	}
 
@@ -49,6 +70,10 @@ func updateClusterConfig(ctx context.Context, cfg aws.Config, dp awsconfig.AWSDa
 		d.AddError("error parsing cpPrometheusPushProxyUrl", err.Error())
 		return
 	}
+	if promPushProxyUri.Scheme != "https" || promPushProxyUri.Hostname() == "" {
+		d.AddError("error parsing cpPrometheusPushProxyUrl", "metrics url must be an https URL with a host: "+config.MetricsUrl.ValueString())
+		return
+	}
 
 	vpcPrivateSubnets := []string{}
 	d.Append(config.PrivateLinkSubnetIds.ElementsAs(ctx, &vpcPrivateSubnets, false)...)
@@ -76,99 +101,137 @@ func updateClusterConfig(ctx context.Context, cfg aws.Config, dp awsconfig.AWSDa
 		customCredentialsEnabled = "enabled"
 	}
 
-	clusterConfig := corev1.Secret{ObjectMeta: v1.ObjectMeta{Name: "cluster-settings", Namespace: "cluster-config"}}
-	_, err = controllerutil.CreateOrUpdate(ctx, kubeClient.Client, &clusterConfig, func() error {
-		clusterConfig.Data = map[string][]byte{
-			"meshID":                           []byte("deltastream"),
-			"stack":                            []byte(config.Stack.ValueString()),
-			"cloud":                            []byte("aws"),
-			"region":                           []byte(cfg.Region),
-			"topology":                         []byte("dp"),
-			"dsEcrAccountID":                   []byte(config.AccountId.ValueString()),
-			"awsAccountID":                     []byte(config.AccountId.ValueString()),
-			"infraID":                          []byte(config.InfraId.ValueString()),
-			"infraName":                        []byte("dp-" + config.InfraId.ValueString()),
-			"resourceID":                       []byte(config.EksResourceId.ValueString()),
-			"clusterName":                      []byte(*cluster.Name),
-			"vpcId":                            []byte(config.VpcId.ValueString()),
-			"vpcCidr":                          []byte(config.VpcCidr.ValueString()),
-			"vpcPrivateSubnetIDs":              []byte(strings.Join(vpcPrivateSubnets, ",")),
-			"clusterPrivateSubnetIDs":          []byte(strings.Join(clusterSubnetIds, ",")),
-			"clusterPrivateSubnetID1":          []byte(clusterSubnetIds[0]),
-			"clusterPrivateSubnetID2":          []byte(clusterSubnetIds[1]),
-			"clusterPrivateSubnetID3":          []byte(clusterSubnetIds[2]),
-			"clusterPublicSubnetIDs":           []byte(strings.Join(clusterPublicSubnetIDs, ",")),
-			"discoveryRegion":                  []byte(cfg.Region),
-			"apiServerURI":                     []byte(*cluster.Endpoint),
-			"apiServerTokenIssuer":             []byte(*cluster.Identity.Oidc.Issuer),
-			"loadbalancerClass":                []byte("service.k8s.aws/nlb"), //hardcode
-			"autoscaleMin":                     []byte("3"),                   //hardcode
-			"autoscaleMax":                     []byte("5"),                   //hardcode
-			"externalSecretsRoleARN":           []byte(config.AwsSecretsManagerRoRoleARN.ValueString()),
-			"infraOperatorRoleARN":             []byte(config.InfraManagerRoleArn.ValueString()),
-			"vaultRoleARN":                     []byte(config.VaultRoleArn.ValueString()),
-			"vaultInitRoleARN":                 []byte(config.VaultInitRoleArn.ValueString()),
-			"lokiRoleARN":                      []byte(config.LokiRoleArn.ValueString()),
-			"tempoRoleARN":                     []byte(config.TempoRoleArn.ValueString()),
-			"thanosStoreGatewayRoleARN":        []byte(config.ThanosStoreGatewayRoleArn.ValueString()),
-			"thanosStoreCompactorRoleARN":      []byte(config.ThanosStoreCompactorRoleArn.ValueString()),
-			"thanosStoreBucketWebRoleARN":      []byte(config.ThanosStoreBucketRoleArn.ValueString()),
-			"thanosSideCarRoleARN":             []byte(config.ThanosSidecarRoleArn.ValueString()),
-			"deadmanAlertRoleARN":              []byte(config.DeadmanAlertRoleArn.ValueString()),
-			"karpenterRoleName":                []byte(config.KarpenterNodeRoleName.ValueString()),
-			"karpenterIrsaARN":                 []byte(config.KarpenterIrsaRoleArn.ValueString()),
-			"storeProxyRoleARN":                []byte(config.StoreProxyRoleArn.ValueString()),
-			"interruptionQueueName":            []byte(config.InterruptionQueueName.ValueString()),
-			"cw2lokiRoleARN":                   []byte(config.Cw2LokiRoleArn.ValueString()),
-			"dpManagerCPAssumeRoleARN":         []byte(config.DpManagerCpRoleArn.ValueString()),
-			"dpManagerRoleARN":                 []byte(config.DpManagerRoleArn.ValueString()),
-			"deltastreamCrossAccountRoleARN":   []byte(config.DsCrossAccountRoleArn.ValueString()),
-			"kafkaRoleARN":                     []byte(config.KafkaRoleArn.ValueString()),
-			"awsLoadBalancerControllerRoleARN": []byte(config.AwsLoadBalancerControllerRoleARN.ValueString()),
-
-			"cpPrometheusPushProxyUrl":    []byte(config.MetricsUrl.ValueString()),
-			"cpPrometheusPushProxyHost":   []byte(promPushProxyUri.Hostname()),
-			"cpPrometheusPushProxyPort":   []byte(`"443"`), //hardcode
-			"grafanaVpcHostname":          []byte(config.O11yHostname.ValueString()),
-			"ciliumPolicyAuditMode":       []byte("false"),  //hardcode
-			"ciliumPolicyEnforcementMode": []byte("always"), //hardcode
-
-			"grafanaIngressMode": []byte("default"), // deprecated
-			"istioIngressMode":   []byte("default"), // deprecated
-
-			"grafanaHostname":            []byte(config.O11yHostname.ValueString()),
-			"o11yEndpointSubnet":         []byte(config.O11ySubnetMode.ValueString()),
-			"o11yTlsTermination":         []byte(config.O11yTlsMode.ValueString()),
-			"grafanaNlbCertificateArn":   []byte(ptr.Deref(config.O11yTlsCertificateArn.ValueStringPointer(), "")),
-			"o11yEndpointSecurityGroups": []byte(ptr.Deref(config.O11yIngressSecurityGroups.ValueStringPointer(), "")),
-
-			"apiHostname":                []byte(config.ApiHostname.ValueString()),
-			"consoleHostname":            []byte(config.ConsoleHostname.ValueString()),
-			"platformVersion":            []byte(config.ProductVersion.ValueString()),
-			"apiEndpointSubnet":          []byte(config.ApiSubnetMode.ValueString()),
-			"apiTlsTermination":          []byte(config.ApiTlsMode.ValueString()),
-			"apiServerNlbCertificateArn": []byte(ptr.Deref(config.ApiTlsCertificateArn.ValueStringPointer(), "")),
-			"apiEndpointSecurityGroups":  []byte(ptr.Deref(config.ApiIngressSecurityGroups.ValueStringPointer(), "")),
-
-			"grafanaPromPushProxVpcHostname": []byte(config.MetricsUrl.ValueString()),
-
-			"prometheusLocalTSDBRetention": []byte("5d"),    //hardcode
-			"prometheusMemoryLimit":        []byte("4Gi"),   //hardcode
-			"prometheusPVCStorageSize":     []byte("300Gi"), //hardcode
-			"thanosQueryMemoryLimit":       []byte("1.2Gi"), //hardcode
-			"thanosStoreMemoryLimit":       []byte("1.2Gi"), //hardcode
-
-			"vpcDnsIP": []byte(config.VpcDnsIP.ValueString()),
-
-			"workloadCredsMode":         []byte(ptr.Deref(config.WorkloadCredentialsMode.ValueStringPointer(), "iamrole")),
-			"dpOperatorUserAwsSecret":   []byte(ptr.Deref(config.WorkloadCredentialsSecret.ValueStringPointer(), "")),
-			"workloadIamRoleArn":        []byte(ptr.Deref(config.WorkloadRoleArn.ValueStringPointer(), "")),
-			"workloadManagerIamRoleArn": []byte(ptr.Deref(config.WorkloadManagerRoleArn.ValueStringPointer(), "")),
-
-			"customCredentialsRoleARN":      []byte(ptr.Deref(config.CustomCredentialsRoleARN.ValueStringPointer(), "")),
-			"enableCustomCredentialsPlugin": []byte(customCredentialsEnabled),
-			"rdsCACertsSecret":              []byte(config.RdsCACertsSecret.ValueString()),
-			"installationTimestamp":         []byte(config.InstallationTimestamp.ValueString()),
+	clusterSettings := map[string][]byte{
+		"meshID":                           []byte("deltastream"),
+		"stack":                            []byte(config.Stack.ValueString()),
+		"cloud":                            []byte("aws"),
+		"region":                           []byte(cfg.Region),
+		"topology":                         []byte("dp"),
+		"dsEcrAccountID":                   []byte(config.AccountId.ValueString()),
+		"awsAccountID":                     []byte(config.AccountId.ValueString()),
+		"infraID":                          []byte(config.InfraId.ValueString()),
+		"infraName":                        []byte("dp-" + config.InfraId.ValueString()),
+		"resourceID":                       []byte(config.EksResourceId.ValueString()),
+		"clusterName":                      []byte(*cluster.Name),
+		"vpcId":                            []byte(config.VpcId.ValueString()),
+		"vpcCidr":                          []byte(config.VpcCidr.ValueString()),
+		"vpcPrivateSubnetIDs":              []byte(strings.Join(vpcPrivateSubnets, ",")),
+		"clusterPrivateSubnetIDs":          []byte(strings.Join(clusterSubnetIds, ",")),
+		"clusterPrivateSubnetID1":          []byte(clusterSubnetIds[0]),
+		"clusterPrivateSubnetID2":          []byte(clusterSubnetIds[1]),
+		"clusterPrivateSubnetID3":          []byte(clusterSubnetIds[2]),
+		"clusterPublicSubnetIDs":           []byte(strings.Join(clusterPublicSubnetIDs, ",")),
+		"discoveryRegion":                  []byte(cfg.Region),
+		"apiServerURI":                     []byte(*cluster.Endpoint),
+		"apiServerTokenIssuer":             []byte(oidcIssuer),
+		"loadbalancerClass":                []byte(config.LoadBalancerClass.ValueString()),
+		"autoscaleMin":                     []byte("3"), //hardcode
+		"autoscaleMax":                     []byte("5"), //hardcode
+		"externalSecretsRoleARN":           []byte(config.AwsSecretsManagerRoRoleARN.ValueString()),
+		"infraOperatorRoleARN":             []byte(config.InfraManagerRoleArn.ValueString()),
+		"vaultRoleARN":                     []byte(config.VaultRoleArn.ValueString()),
+		"vaultInitRoleARN":                 []byte(config.VaultInitRoleArn.ValueString()),
+		"lokiRoleARN":                      []byte(config.LokiRoleArn.ValueString()),
+		"tempoRoleARN":                     []byte(config.TempoRoleArn.ValueString()),
+		"thanosStoreGatewayRoleARN":        []byte(config.ThanosStoreGatewayRoleArn.ValueString()),
+		"thanosStoreCompactorRoleARN":      []byte(config.ThanosStoreCompactorRoleArn.ValueString()),
+		"thanosStoreBucketWebRoleARN":      []byte(config.ThanosStoreBucketRoleArn.ValueString()),
+		"thanosSideCarRoleARN":             []byte(config.ThanosSidecarRoleArn.ValueString()),
+		"deadmanAlertRoleARN":              []byte(config.DeadmanAlertRoleArn.ValueString()),
+		"karpenterRoleName":                []byte(config.KarpenterNodeRoleName.ValueString()),
+		"karpenterIrsaARN":                 []byte(config.KarpenterIrsaRoleArn.ValueString()),
+		"storeProxyRoleARN":                []byte(config.StoreProxyRoleArn.ValueString()),
+		"interruptionQueueName":            []byte(config.InterruptionQueueName.ValueString()),
+		"dpManagerCPAssumeRoleARN":         []byte(config.DpManagerCpRoleArn.ValueString()),
+		"dpManagerRoleARN":                 []byte(config.DpManagerRoleArn.ValueString()),
+		"deltastreamCrossAccountRoleARN":   []byte(config.DsCrossAccountRoleArn.ValueString()),
+		"kafkaRoleARN":                     []byte(config.KafkaRoleArn.ValueString()),
+		"awsLoadBalancerControllerRoleARN": []byte(config.AwsLoadBalancerControllerRoleARN.ValueString()),
+		"datagenRoleARN":                   []byte(ptr.Deref(config.DatagenRoleArn.ValueStringPointer(), "")),
+		"nthRoleARN":                       []byte(ptr.Deref(config.NthRoleArn.ValueStringPointer(), "")),
+		"nthCordonOnly":                    []byte(strconv.FormatBool(config.NthCordonOnly.ValueBool())),
+		"defaultInstanceProfile":           []byte(ptr.Deref(config.DefaultInstanceProfile.ValueStringPointer(), "")),
+
+		"cpPrometheusPushProxyUrl":    []byte(config.MetricsUrl.ValueString()),
+		"cpPrometheusPushProxyHost":   []byte(promPushProxyUri.Hostname()),
+		"cpPrometheusPushProxyPort":   []byte(`"443"`), //hardcode
+		"grafanaVpcHostname":          []byte(config.O11yHostname.ValueString()),
+		"networkMode":                 []byte(config.NetworkMode.ValueString()),
+		"ciliumPolicyAuditMode":       []byte(strconv.FormatBool(config.CiliumPolicyAuditMode.ValueBool())),
+		"ciliumPolicyEnforcementMode": []byte(config.CiliumPolicyEnforcementMode.ValueString()),
+
+		"grafanaIngressMode": []byte("default"), // deprecated
+		"istioIngressMode":   []byte("default"), // deprecated
+
+		"grafanaHostname":            []byte(config.O11yHostname.ValueString()),
+		"o11yEndpointSubnet":         []byte(config.O11ySubnetMode.ValueString()),
+		"o11yTlsTermination":         []byte(config.O11yTlsMode.ValueString()),
+		"grafanaNlbCertificateArn":   []byte(ptr.Deref(config.O11yTlsCertificateArn.ValueStringPointer(), "")),
+		"o11yEndpointSecurityGroups": []byte(strings.Join(awsconfig.SplitSecurityGroups(config.O11yIngressSecurityGroups.ValueString()), ",")),
+		"o11yAcmeEmail":              []byte(ptr.Deref(config.O11yAcmeEmail.ValueStringPointer(), "")),
+
+		"apiHostname":                []byte(config.ApiHostname.ValueString()),
+		"consoleHostname":            []byte(config.ConsoleHostname.ValueString()),
+		"platformVersion":            []byte(config.ProductVersion.ValueString()),
+		"apiEndpointSubnet":          []byte(config.ApiSubnetMode.ValueString()),
+		"apiTlsTermination":          []byte(config.ApiTlsMode.ValueString()),
+		"apiServerNlbCertificateArn": []byte(ptr.Deref(config.ApiTlsCertificateArn.ValueStringPointer(), "")),
+		"apiEndpointSecurityGroups":  []byte(strings.Join(awsconfig.SplitSecurityGroups(config.ApiIngressSecurityGroups.ValueString()), ",")),
+		"apiAcmeEmail":               []byte(ptr.Deref(config.ApiAcmeEmail.ValueStringPointer(), "")),
+
+		"grafanaPromPushProxVpcHostname": []byte(config.MetricsUrl.ValueString()),
+
+		"prometheusLocalTSDBRetention": []byte(observability.PrometheusLocalTsdbRetention.ValueString()),
+		"prometheusMemoryLimit":        []byte(observability.PrometheusMemoryLimit.ValueString()),
+		"prometheusPVCStorageSize":     []byte(observability.PrometheusPvcStorageSize.ValueString()),
+		"thanosQueryMemoryLimit":       []byte(observability.ThanosQueryMemoryLimit.ValueString()),
+		"thanosStoreMemoryLimit":       []byte(observability.ThanosStoreMemoryLimit.ValueString()),
+
+		"vpcDnsIP":    []byte(config.VpcDnsIP.ValueString()),
+		"ipFamily":    []byte(config.IpFamily.ValueString()),
+		"vpcIpv6Cidr": []byte(ptr.Deref(config.VpcIpv6Cidr.ValueStringPointer(), "")),
+
+		"workloadCredsMode":         []byte(ptr.Deref(config.WorkloadCredentialsMode.ValueStringPointer(), "iamrole")),
+		"dpOperatorUserAwsSecret":   []byte(ptr.Deref(config.WorkloadCredentialsSecret.ValueStringPointer(), "")),
+		"workloadIamRoleArn":        []byte(ptr.Deref(config.WorkloadRoleArn.ValueStringPointer(), "")),
+		"workloadManagerIamRoleArn": []byte(ptr.Deref(config.WorkloadManagerRoleArn.ValueStringPointer(), "")),
+
+		"customCredentialsRoleARN":      []byte(ptr.Deref(config.CustomCredentialsRoleARN.ValueStringPointer(), "")),
+		"enableCustomCredentialsPlugin": []byte(customCredentialsEnabled),
+		"rdsCACertsSecret":              []byte(config.RdsCACertsSecret.ValueString()),
+		"installationTimestamp":         []byte(config.InstallationTimestamp.ValueString()),
+
+		"clusterConfigNamespace": []byte(namespaces.ClusterConfig),
+		"deltastreamNamespace":   []byte(namespaces.DeltaStream),
+	}
+	if !config.Cw2LokiSqsUrl.IsNull() {
+		clusterSettings["cw2lokiRoleARN"] = []byte(config.Cw2LokiRoleArn.ValueString())
+	}
+
+	tflog.Debug(ctx, "rendered cluster settings", map[string]any{"settings": util.RedactSettings(clusterSettings)})
+
+	changedKeys := []string{}
+	clusterConfig := corev1.Secret{ObjectMeta: v1.ObjectMeta{Name: "cluster-settings", Namespace: namespaces.ClusterConfig}}
+	if kubeClient.DryRun() {
+		if err := kubeClient.Get(ctx, client.ObjectKeyFromObject(&clusterConfig), &clusterConfig); err != nil && !k8serrors.IsNotFound(err) {
+			d.AddError("error reading cluster settings", err.Error())
+			return
+		}
+		if changedKeys = changedSecretKeys(clusterConfig.Data, clusterSettings); len(changedKeys) > 0 {
+			d.AddWarning(fmt.Sprintf("dry run: %d cluster settings would change", len(changedKeys)), strings.Join(changedKeys, "\n"))
+		}
+		return
+	}
+	op, err := controllerutil.CreateOrUpdate(ctx, kubeClient.Client, &clusterConfig, func() error {
+		changedKeys = changedSecretKeys(clusterConfig.Data, clusterSettings)
+		if len(changedKeys) > 0 {
+			// snapshot the current settings so that a broken change can be rolled back by hand
+			if !clusterConfig.CreationTimestamp.IsZero() && config.ClusterSettingsBackupCount.ValueInt64() > 0 {
+				if err := backupClusterSettings(ctx, kubeClient, &clusterConfig, int(config.ClusterSettingsBackupCount.ValueInt64())); err != nil {
+					return err
+				}
+			}
+			clusterConfig.Data = clusterSettings
 		}
 		return nil
 	})
@@ -176,6 +239,61 @@ func updateClusterConfig(ctx context.Context, cfg aws.Config, dp awsconfig.AWSDa
 		d.AddError("error setup cluster settings", err.Error())
 		return
 	}
+	tflog.Debug(ctx, "cluster settings reconciled", map[string]any{"operation": op, "changed keys": changedKeys})
 
 	return
 }
+
+const clusterSettingsBackupLabel = "dataplane.deltastream.io/backup-of"
+
+// backupClusterSettings copies the current cluster settings into a timestamped secret and removes the oldest backups
+// beyond retain.
+func backupClusterSettings(ctx context.Context, kubeClient *util.RetryableClient, current *corev1.Secret, retain int) error {
+	backup := &corev1.Secret{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      current.Name + "-backup-" + time.Now().UTC().Format("20060102150405"),
+			Namespace: current.Namespace,
+			Labels:    map[string]string{clusterSettingsBackupLabel: current.Name},
+			Annotations: map[string]string{
+				installedProviderVersionAnnotation: current.Annotations[installedProviderVersionAnnotation],
+				installedProductVersionAnnotation:  current.Annotations[installedProductVersionAnnotation],
+			},
+		},
+		Data: current.Data,
+	}
+	tflog.Debug(ctx, "backing up cluster settings", map[string]any{"backup": backup.Name})
+	if err := kubeClient.Create(ctx, backup); err != nil && !k8serrors.IsAlreadyExists(err) {
+		return fmt.Errorf("unable to back up %s: %w", current.Name, err)
+	}
+
+	backups := corev1.SecretList{}
+	if err := kubeClient.List(ctx, &backups, client.InNamespace(current.Namespace), client.MatchingLabels{clusterSettingsBackupLabel: current.Name}); err != nil {
+		return fmt.Errorf("unable to list %s backups: %w", current.Name, err)
+	}
+	// backup names sort by the time they were taken
+	sort.Slice(backups.Items, func(i, j int) bool { return backups.Items[i].Name < backups.Items[j].Name })
+	for i := 0; i < len(backups.Items)-retain; i++ {
+		tflog.Debug(ctx, "removing cluster settings backup", map[string]any{"backup": backups.Items[i].Name})
+		if err := kubeClient.Delete(ctx, &backups.Items[i]); err != nil && !k8serrors.IsNotFound(err) {
+			return fmt.Errorf("unable to remove backup %s: %w", backups.Items[i].Name, err)
+		}
+	}
+	return nil
+}
+
+// changedSecretKeys returns the sorted keys that were added, modified or removed between the current and desired data.
+func changedSecretKeys(current, desired map[string][]byte) []string {
+	changed := []string{}
+	for k, v := range desired {
+		if cv, ok := current[k]; !ok || !bytes.Equal(cv, v) {
+			changed = append(changed, k)
+		}
+	}
+	for k := range current {
+		if _, ok := desired[k]; !ok {
+			changed = append(changed, k)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
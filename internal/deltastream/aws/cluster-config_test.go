@@ -0,0 +1,62 @@
+// Copyright (c) DeltaStream, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package aws
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestChangedSecretKeys(t *testing.T) {
+	tests := []struct {
+		name    string
+		current map[string][]byte
+		desired map[string][]byte
+		want    []string
+	}{
+		{
+			name:    "unchanged",
+			current: map[string][]byte{"a": []byte("1"), "b": []byte("2")},
+			desired: map[string][]byte{"a": []byte("1"), "b": []byte("2")},
+			want:    []string{},
+		},
+		{
+			name:    "added key",
+			current: map[string][]byte{"a": []byte("1")},
+			desired: map[string][]byte{"a": []byte("1"), "b": []byte("2")},
+			want:    []string{"b"},
+		},
+		{
+			name:    "removed key",
+			current: map[string][]byte{"a": []byte("1"), "b": []byte("2")},
+			desired: map[string][]byte{"a": []byte("1")},
+			want:    []string{"b"},
+		},
+		{
+			name:    "changed value",
+			current: map[string][]byte{"a": []byte("1"), "b": []byte("2")},
+			desired: map[string][]byte{"a": []byte("1"), "b": []byte("3")},
+			want:    []string{"b"},
+		},
+		{
+			name:    "added, removed and changed keys are sorted",
+			current: map[string][]byte{"z": []byte("1"), "m": []byte("2"), "k": []byte("3")},
+			desired: map[string][]byte{"m": []byte("changed"), "k": []byte("3"), "a": []byte("new")},
+			want:    []string{"a", "m", "z"},
+		},
+		{
+			name:    "empty current secret",
+			current: nil,
+			desired: map[string][]byte{"b": []byte("2"), "a": []byte("1")},
+			want:    []string{"a", "b"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := changedSecretKeys(tt.current, tt.desired); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("changedSecretKeys() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright (c) DeltaStream, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+)
+
+type AWSDataplaneClusterSettings struct {
+	AssumeRole              basetypes.ObjectValue `tfsdk:"assume_role"`
+	Stack                   basetypes.StringValue `tfsdk:"stack"`
+	InfraId                 basetypes.StringValue `tfsdk:"infra_id"`
+	EksResourceId           basetypes.StringValue `tfsdk:"eks_resource_id"`
+	ClusterIndex            basetypes.Int64Value  `tfsdk:"cluster_index"`
+	ClusterConfigNamespace  basetypes.StringValue `tfsdk:"cluster_config_namespace"`
+	KubeApiEndpointOverride basetypes.StringValue `tfsdk:"kube_api_endpoint_override"`
+	Settings                basetypes.MapValue    `tfsdk:"settings"`
+}
+
+func (d *AWSDataplaneClusterSettings) AssumeRoleData(ctx context.Context) (AssumeRole, diag.Diagnostics) {
+	var ar AssumeRole
+	diag := d.AssumeRole.As(ctx, &ar, basetypes.ObjectAsOptions{})
+	return ar, diag
+}
+
+// Namespaces resolves the namespaces of the dataplane the same way the dataplane resource does.
+func (d *AWSDataplaneClusterSettings) Namespaces() Namespaces {
+	return ClusterConfiguration{ClusterConfigNamespace: d.ClusterConfigNamespace}.Namespaces()
+}
+
+var ClusterSettingsDataSourceSchema = schema.Schema{
+	MarkdownDescription: "Current values of the DeltaStream dataplane cluster settings. Credential-like values are redacted.",
+
+	Attributes: map[string]schema.Attribute{
+		"assume_role": assumeRoleDataSourceAttribute,
+		"stack": schema.StringAttribute{
+			Description: "The type of DeltaStream dataplane (default: prod).",
+			Optional:    true,
+			Validators:  []validator.String{stringvalidator.OneOf(Stacks...)},
+		},
+		"infra_id": schema.StringAttribute{
+			Description: "The infra ID of the DeltaStream dataplane (provided by DeltaStream).",
+			Required:    true,
+		},
+		"eks_resource_id": schema.StringAttribute{
+			Description: "The resource ID of the DeltaStream dataplane (provided by DeltaStream).",
+			Required:    true,
+		},
+		"cluster_index": schema.Int64Attribute{
+			Description: "The index of the cluster (default: 0).",
+			Optional:    true,
+		},
+		"cluster_config_namespace": schema.StringAttribute{
+			Description: "The namespace holding the cluster settings secret (default: cluster-config).",
+			Optional:    true,
+		},
+		"kube_api_endpoint_override": schema.StringAttribute{
+			Description: "Overrides the endpoint used to reach the kube API server.",
+			Optional:    true,
+		},
+		"settings": schema.MapAttribute{
+			Description: "The cluster settings keys and values. Credential-like values are redacted.",
+			ElementType: basetypes.StringType{},
+			Computed:    true,
+		},
+	},
+}
@@ -0,0 +1,48 @@
+// Copyright (c) DeltaStream, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+)
+
+// assumeRoleDataSourceAttribute is the assume role configuration shared by the data sources.
+var assumeRoleDataSourceAttribute = schema.SingleNestedAttribute{
+	Description: "Assume role configuration",
+	Required:    true,
+	Attributes: map[string]schema.Attribute{
+		"role_arn": schema.StringAttribute{
+			Description: "Amazon Resource Name (ARN) of an IAM Role to assume prior to making API calls.",
+			Optional:    true,
+		},
+		"via_role_arns": schema.ListAttribute{
+			Description: "Ordered list of intermediary IAM Role ARNs to assume, each with the credentials of the previous one, before assuming role_arn. AWS limits chained role sessions to one hour.",
+			ElementType: basetypes.StringType{},
+			Optional:    true,
+		},
+		"session_name": schema.StringAttribute{
+			Description: "An identifier for the assumed role session.",
+			Optional:    true,
+			Validators:  []validator.String{SessionName()},
+		},
+		"region": schema.StringAttribute{
+			Description: "The AWS region to use for the assume role.",
+			Optional:    true,
+			Validators:  []validator.String{Region()},
+		},
+		"session_tags": schema.MapAttribute{
+			Description: "Session tags to pass when assuming the role, for use in attribute-based access control policies.",
+			ElementType: basetypes.StringType{},
+			Optional:    true,
+			Validators:  []validator.Map{SessionTags()},
+		},
+		"source_identity": schema.StringAttribute{
+			Description: "The source identity to set on the assumed role session, recorded in CloudTrail.",
+			Optional:    true,
+			Validators:  []validator.String{SourceIdentity()},
+		},
+	},
+}
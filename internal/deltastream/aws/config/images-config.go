@@ -0,0 +1,54 @@
+// Copyright (c) DeltaStream, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+)
+
+type AWSDataplaneImages struct {
+	AssumeRole        basetypes.ObjectValue `tfsdk:"assume_role"`
+	Stack             basetypes.StringValue `tfsdk:"stack"`
+	ProductVersion    basetypes.StringValue `tfsdk:"product_version"`
+	Images            basetypes.ListValue   `tfsdk:"images"`
+	ExecEngineVersion basetypes.StringValue `tfsdk:"exec_engine_version"`
+}
+
+func (d *AWSDataplaneImages) AssumeRoleData(ctx context.Context) (AssumeRole, diag.Diagnostics) {
+	var ar AssumeRole
+	diag := d.AssumeRole.As(ctx, &ar, basetypes.ObjectAsOptions{})
+	return ar, diag
+}
+
+var ImagesDataSourceSchema = schema.Schema{
+	MarkdownDescription: "Container images pulled by a DeltaStream dataplane product version",
+
+	Attributes: map[string]schema.Attribute{
+		"assume_role": assumeRoleDataSourceAttribute,
+		"stack": schema.StringAttribute{
+			Description: "The type of DeltaStream dataplane (default: prod).",
+			Optional:    true,
+			Validators:  []validator.String{stringvalidator.OneOf(Stacks...)},
+		},
+		"product_version": schema.StringAttribute{
+			Description: "The product version to list the images of.",
+			Required:    true,
+		},
+		"images": schema.ListAttribute{
+			Description: "The images pulled by the product version.",
+			ElementType: basetypes.StringType{},
+			Computed:    true,
+		},
+		"exec_engine_version": schema.StringAttribute{
+			Description: "The execution engine version of the product version.",
+			Computed:    true,
+		},
+	},
+}
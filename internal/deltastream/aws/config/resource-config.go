@@ -7,11 +7,15 @@ import (
 	"context"
 	"regexp"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
@@ -20,29 +24,88 @@ import (
 type AWSDataplane struct {
 	AssumeRole           basetypes.ObjectValue `tfsdk:"assume_role"`
 	ClusterConfiguration basetypes.ObjectValue `tfsdk:"configuration"`
+	Tags                 basetypes.MapValue    `tfsdk:"tags"`
 	Status               basetypes.ObjectValue `tfsdk:"status"`
 }
 
 type AssumeRole struct {
-	RoleArn     basetypes.StringValue `tfsdk:"role_arn"`
-	SessionName basetypes.StringValue `tfsdk:"session_name"`
-	Region      basetypes.StringValue `tfsdk:"region"`
+	RoleArn        basetypes.StringValue `tfsdk:"role_arn"`
+	ViaRoleArns    basetypes.ListValue   `tfsdk:"via_role_arns"`
+	SessionName    basetypes.StringValue `tfsdk:"session_name"`
+	Region         basetypes.StringValue `tfsdk:"region"`
+	SessionTags    basetypes.MapValue    `tfsdk:"session_tags"`
+	SourceIdentity basetypes.StringValue `tfsdk:"source_identity"`
 }
 
 type Status struct {
 	ProviderVersion basetypes.StringValue `tfsdk:"provider_version"`
 	ProductVersion  basetypes.StringValue `tfsdk:"product_version"`
+	CreatedAt       basetypes.StringValue `tfsdk:"created_at"`
 	LastModified    basetypes.StringValue `tfsdk:"last_modified"`
+	Phase           basetypes.StringValue `tfsdk:"phase"`
+	ClusterEndpoint basetypes.StringValue `tfsdk:"cluster_endpoint"`
+	OidcIssuer      basetypes.StringValue `tfsdk:"oidc_issuer"`
 }
 
 func (m Status) AttributeTypes() map[string]attr.Type {
 	return map[string]attr.Type{
 		"provider_version": types.StringType,
 		"product_version":  types.StringType,
+		"created_at":       types.StringType,
 		"last_modified":    types.StringType,
+		"phase":            types.StringType,
+		"cluster_endpoint": types.StringType,
+		"oidc_issuer":      types.StringType,
 	}
 }
 
+const (
+	PhasePreflight                  = "preflight"
+	PhaseCopyingImages              = "copying_images"
+	PhaseUpdatingTrustPolicies      = "updating_trust_policies"
+	PhaseRemovingAwsNode            = "removing_aws_node"
+	PhaseInstallingCilium           = "installing_cilium"
+	PhaseConfiguringCluster         = "configuring_cluster"
+	PhaseInstallingDeltaStream      = "installing_deltastream"
+	PhaseWaitingForServices         = "waiting_for_services"
+	PhaseDeployingCustomCredentials = "deploying_custom_credentials"
+	PhaseVerifyingWorkloads         = "verifying_workloads"
+	PhaseReady                      = "ready"
+)
+
+const (
+	KafkaAuthModeIam   = "iam"
+	KafkaAuthModeScram = "scram"
+)
+
+const (
+	RdsAuthModePassword = "password"
+	RdsAuthModeIam      = "iam"
+
+	IpFamilyIpv4      = "ipv4"
+	IpFamilyDualStack = "dualstack"
+)
+
+const (
+	ImageDeliveryModeCopy        = "copy"
+	ImageDeliveryModePullThrough = "pull_through"
+)
+
+const (
+	ImageSignaturePolicyAcceptAnything = "accept_anything"
+	ImageSignaturePolicyVerify         = "verify"
+)
+
+const (
+	DeletionModeDestroy = "destroy"
+	DeletionModeRelease = "release"
+)
+
+const (
+	NetworkModeCiliumEni      = "cilium-eni"
+	NetworkModeAwsCniChaining = "aws-cni-chaining"
+)
+
 type ClusterConfiguration struct {
 	Stack       basetypes.StringValue `tfsdk:"stack"`
 	DsAccountId basetypes.StringValue `tfsdk:"ds_account_id"`
@@ -54,19 +117,31 @@ type ClusterConfiguration struct {
 	ClusterIndex   basetypes.Int64Value  `tfsdk:"cluster_index"`
 	ProductVersion basetypes.StringValue `tfsdk:"product_version"`
 
+	ManifestsSourceUrl basetypes.StringValue `tfsdk:"manifests_source_url"`
+	ManifestsSourceRef basetypes.StringValue `tfsdk:"manifests_source_ref"`
+
 	VpcId                basetypes.StringValue `tfsdk:"vpc_id"`
 	VpcCidr              basetypes.StringValue `tfsdk:"vpc_cidr"`
 	VpcDnsIP             basetypes.StringValue `tfsdk:"vpc_dns_ip"`
+	VpcIpv6Cidr          basetypes.StringValue `tfsdk:"vpc_ipv6_cidr"`
+	IpFamily             basetypes.StringValue `tfsdk:"ip_family"`
 	PrivateLinkSubnetIds basetypes.ListValue   `tfsdk:"private_link_subnets_ids"`
 
-	PrivateSubnetIds       basetypes.ListValue   `tfsdk:"private_subnet_ids"`
-	PublicSubnetIds        basetypes.ListValue   `tfsdk:"public_subnet_ids"`
-	MetricsUrl             basetypes.StringValue `tfsdk:"metrics_url"`
-	InterruptionQueueName  basetypes.StringValue `tfsdk:"interruption_queue_name"`
-	ProductArtifactsBucket basetypes.StringValue `tfsdk:"product_artifacts_bucket"`
-	SerdeBucket            basetypes.StringValue `tfsdk:"serde_bucket"`
-	WorkloadStateBucket    basetypes.StringValue `tfsdk:"workload_state_bucket"`
-	O11yBucket             basetypes.StringValue `tfsdk:"o11y_bucket"`
+	KubeApiEndpointOverride basetypes.StringValue `tfsdk:"kube_api_endpoint_override"`
+	ManageAccessEntry       basetypes.BoolValue   `tfsdk:"manage_access_entry"`
+	ClusterActiveTimeout    basetypes.StringValue `tfsdk:"cluster_active_timeout"`
+
+	PrivateSubnetIds        basetypes.ListValue   `tfsdk:"private_subnet_ids"`
+	PublicSubnetIds         basetypes.ListValue   `tfsdk:"public_subnet_ids"`
+	MetricsUrl              basetypes.StringValue `tfsdk:"metrics_url"`
+	InterruptionQueueName   basetypes.StringValue `tfsdk:"interruption_queue_name"`
+	ManageInterruptionQueue basetypes.BoolValue   `tfsdk:"manage_interruption_queue"`
+	ProductArtifactsBucket  basetypes.StringValue `tfsdk:"product_artifacts_bucket"`
+	SerdeBucket             basetypes.StringValue `tfsdk:"serde_bucket"`
+	SerdeBucketRegion       basetypes.StringValue `tfsdk:"serde_bucket_region"`
+	WorkloadStateBucket     basetypes.StringValue `tfsdk:"workload_state_bucket"`
+	O11yBucket              basetypes.StringValue `tfsdk:"o11y_bucket"`
+	O11yBucketRegion        basetypes.StringValue `tfsdk:"o11y_bucket_region"`
 
 	AwsSecretsManagerRoRoleARN       basetypes.StringValue `tfsdk:"aws_secrets_manager_ro_role_arn"`
 	InfraManagerRoleArn              basetypes.StringValue `tfsdk:"infra_manager_role_arn"`
@@ -90,9 +165,15 @@ type ClusterConfiguration struct {
 	KafkaRoleArn                     basetypes.StringValue `tfsdk:"kafka_role_arn"`
 	KafkaRoleExternalId              basetypes.StringValue `tfsdk:"kafka_role_external_id"`
 	AwsLoadBalancerControllerRoleARN basetypes.StringValue `tfsdk:"aws_load_balancer_controller_role_arn"`
+	DatagenRoleArn                   basetypes.StringValue `tfsdk:"datagen_role_arn"`
+	NthRoleArn                       basetypes.StringValue `tfsdk:"nth_role_arn"`
+	NthCordonOnly                    basetypes.BoolValue   `tfsdk:"nth_cordon_only"`
+	DefaultInstanceProfile           basetypes.StringValue `tfsdk:"default_instance_profile"`
 
-	CustomCredentialsRoleARN basetypes.StringValue `tfsdk:"custom_credentials_role_arn"`
-	CustomCredentialsImage   basetypes.StringValue `tfsdk:"custom_credentials_image"`
+	CustomCredentialsRoleARN        basetypes.StringValue `tfsdk:"custom_credentials_role_arn"`
+	CustomCredentialsImage          basetypes.StringValue `tfsdk:"custom_credentials_image"`
+	CustomCredentialsRolloutTimeout basetypes.StringValue `tfsdk:"custom_credentials_rollout_timeout"`
+	StrictTrustPolicy               basetypes.BoolValue   `tfsdk:"strict_trust_policy"`
 
 	WorkloadCredentialsMode   basetypes.StringValue `tfsdk:"workload_credentials_mode"`
 	WorkloadCredentialsSecret basetypes.StringValue `tfsdk:"workload_credentials_secret"`
@@ -104,12 +185,26 @@ type ClusterConfiguration struct {
 	O11yTlsMode               basetypes.StringValue `tfsdk:"o11y_tls_mode"`
 	O11yTlsCertificateArn     basetypes.StringValue `tfsdk:"o11y_tls_certificate_arn"`
 	O11yIngressSecurityGroups basetypes.StringValue `tfsdk:"o11y_ingress_security_groups"`
+	O11yAcmeEmail             basetypes.StringValue `tfsdk:"o11y_acme_email"`
 
 	ApiHostname              basetypes.StringValue `tfsdk:"api_hostname"`
 	ApiSubnetMode            basetypes.StringValue `tfsdk:"api_subnet_mode"`
 	ApiTlsMode               basetypes.StringValue `tfsdk:"api_tls_mode"`
 	ApiTlsCertificateArn     basetypes.StringValue `tfsdk:"api_tls_certificate_arn"`
 	ApiIngressSecurityGroups basetypes.StringValue `tfsdk:"api_ingress_security_groups"`
+	ApiAcmeEmail             basetypes.StringValue `tfsdk:"api_acme_email"`
+
+	ValidateDns basetypes.BoolValue `tfsdk:"validate_dns"`
+
+	LoadBalancerClass basetypes.StringValue `tfsdk:"loadbalancer_class"`
+
+	NetworkMode                 basetypes.StringValue `tfsdk:"network_mode"`
+	CiliumPolicyAuditMode       basetypes.BoolValue   `tfsdk:"cilium_policy_audit_mode"`
+	CiliumPolicyEnforcementMode basetypes.StringValue `tfsdk:"cilium_policy_enforcement_mode"`
+	CiliumVersion               basetypes.StringValue `tfsdk:"cilium_version"`
+	CiliumChartRepository       basetypes.StringValue `tfsdk:"cilium_chart_repository"`
+	CiliumNodesReadyTimeout     basetypes.StringValue `tfsdk:"cilium_nodes_ready_timeout"`
+	CiliumInstallTimeout        basetypes.StringValue `tfsdk:"cilium_install_timeout"`
 
 	KmsKeyId          basetypes.StringValue `tfsdk:"kms_key_id"`
 	DynamoDbTableName basetypes.StringValue `tfsdk:"dynamodb_table_name"`
@@ -117,9 +212,20 @@ type ClusterConfiguration struct {
 	KafkaHosts         basetypes.ListValue   `tfsdk:"kafka_hosts"`
 	KafkaListenerPorts basetypes.ListValue   `tfsdk:"kafka_listener_ports"`
 	KafkaClusterName   basetypes.StringValue `tfsdk:"kafka_cluster_name"`
+	KafkaAuthMode      basetypes.StringValue `tfsdk:"kafka_auth_mode"`
+	KafkaScramSecret   basetypes.StringValue `tfsdk:"kafka_scram_secret"`
 
-	RdsResourceID basetypes.StringValue `tfsdk:"rds_resource_id"`
-	Cw2LokiSqsUrl basetypes.StringValue `tfsdk:"cw2loki_sqs_url"`
+	RdsResourceID           basetypes.StringValue `tfsdk:"rds_resource_id"`
+	RdsCredentialsSecretId  basetypes.StringValue `tfsdk:"rds_credentials_secret_id"`
+	RdsAuthMode             basetypes.StringValue `tfsdk:"rds_auth_mode"`
+	RdsIamRoleArn           basetypes.StringValue `tfsdk:"rds_iam_role_arn"`
+	RdsHost                 basetypes.StringValue `tfsdk:"rds_host"`
+	RdsPort                 basetypes.Int64Value  `tfsdk:"rds_port"`
+	RdsDatabase             basetypes.StringValue `tfsdk:"rds_database"`
+	RdsUsername             basetypes.StringValue `tfsdk:"rds_username"`
+	ValidateRdsConnectivity basetypes.BoolValue   `tfsdk:"validate_rds_connectivity"`
+	ValidateRdsTls          basetypes.BoolValue   `tfsdk:"validate_rds_tls"`
+	Cw2LokiSqsUrl           basetypes.StringValue `tfsdk:"cw2loki_sqs_url"`
 
 	ControlPlaneKafkaHosts         basetypes.ListValue `tfsdk:"cp_kafka_hosts"`
 	ControlPlaneKafkaListenerPorts basetypes.ListValue `tfsdk:"cp_kafka_listener_ports"`
@@ -128,6 +234,121 @@ type ClusterConfiguration struct {
 	RdsCACertsSecret basetypes.StringValue `tfsdk:"rds_ca_certs_secret"`
 
 	InstallationTimestamp basetypes.StringValue `tfsdk:"installation_timestamp"`
+
+	NodeClaimDrainTimeout            basetypes.StringValue `tfsdk:"node_claim_drain_timeout"`
+	PodEvictionGracePeriod           basetypes.StringValue `tfsdk:"pod_eviction_grace_period"`
+	SecretDeletionRecoveryWindowDays basetypes.Int64Value  `tfsdk:"secret_deletion_recovery_window_days"`
+	RetainSecretsOnDestroy           basetypes.BoolValue   `tfsdk:"retain_secrets_on_destroy"`
+	SkipLoadBalancerCleanup          basetypes.BoolValue   `tfsdk:"skip_loadbalancer_cleanup"`
+	ForceDestroy                     basetypes.BoolValue   `tfsdk:"force_destroy"`
+	DeletionMode                     basetypes.StringValue `tfsdk:"deletion_mode"`
+	CreateEcrRepositories            basetypes.BoolValue   `tfsdk:"create_ecr_repositories"`
+	ClusterSettingsBackupCount       basetypes.Int64Value  `tfsdk:"cluster_settings_backup_count"`
+	ImageDeliveryMode                basetypes.StringValue `tfsdk:"image_delivery_mode"`
+	ImageCopyConcurrency             basetypes.Int64Value  `tfsdk:"image_copy_concurrency"`
+	ImageCopyRateLimit               basetypes.StringValue `tfsdk:"image_copy_rate_limit"`
+	ImageReplicaRegions              basetypes.ListValue   `tfsdk:"image_replica_regions"`
+	ImageReplicaBestEffort           basetypes.BoolValue   `tfsdk:"image_replica_best_effort"`
+	ImageSignaturePolicy             basetypes.StringValue `tfsdk:"image_signature_policy"`
+	ImageSignaturePublicKey          basetypes.StringValue `tfsdk:"image_signature_public_key"`
+	ImageList                        basetypes.ObjectValue `tfsdk:"image_list"`
+	Observability                    basetypes.ObjectValue `tfsdk:"observability"`
+	CrdEstablishedTimeout            basetypes.StringValue `tfsdk:"crd_established_timeout"`
+	KustomizationReconcileTimeout    basetypes.StringValue `tfsdk:"kustomization_reconcile_timeout"`
+	WorkloadReadyTimeout             basetypes.StringValue `tfsdk:"workload_ready_timeout"`
+	OrderedUpgrade                   basetypes.BoolValue   `tfsdk:"ordered_upgrade"`
+
+	ClusterConfigNamespace basetypes.StringValue `tfsdk:"cluster_config_namespace"`
+	DeltaStreamNamespace   basetypes.StringValue `tfsdk:"deltastream_namespace"`
+}
+
+// Namespaces holds the kubernetes namespaces used by the dataplane
+type Namespaces struct {
+	ClusterConfig string
+	DeltaStream   string
+	DpOperator    string
+	IstioSystem   string
+	FluxSystem    string
+	KubeSystem    string
+}
+
+const (
+	DefaultClusterConfigNamespace = "cluster-config"
+	DefaultDeltaStreamNamespace   = "deltastream"
+)
+
+// Namespaces resolves the namespaces for the cluster configuration, unset namespaces resolve to their defaults.
+func (cc ClusterConfiguration) Namespaces() Namespaces {
+	return Namespaces{
+		ClusterConfig: namespaceOrDefault(cc.ClusterConfigNamespace, DefaultClusterConfigNamespace),
+		DeltaStream:   namespaceOrDefault(cc.DeltaStreamNamespace, DefaultDeltaStreamNamespace),
+		DpOperator:    "dp-operator",
+		IstioSystem:   "istio-system",
+		FluxSystem:    "flux-system",
+		KubeSystem:    "kube-system",
+	}
+}
+
+func namespaceOrDefault(namespace basetypes.StringValue, def string) string {
+	if namespace.IsNull() || namespace.IsUnknown() || namespace.ValueString() == "" {
+		return def
+	}
+	return namespace.ValueString()
+}
+
+type ImageList struct {
+	Images            basetypes.ListValue   `tfsdk:"images"`
+	ExecEngineVersion basetypes.StringValue `tfsdk:"exec_engine_version"`
+}
+
+func (cc *ClusterConfiguration) ImageListData(ctx context.Context) (ImageList, diag.Diagnostics) {
+	var il ImageList
+	diag := cc.ImageList.As(ctx, &il, basetypes.ObjectAsOptions{})
+	return il, diag
+}
+
+type Observability struct {
+	PrometheusLocalTsdbRetention basetypes.StringValue `tfsdk:"prometheus_local_tsdb_retention"`
+	PrometheusMemoryLimit        basetypes.StringValue `tfsdk:"prometheus_memory_limit"`
+	PrometheusPvcStorageSize     basetypes.StringValue `tfsdk:"prometheus_pvc_storage_size"`
+	ThanosQueryMemoryLimit       basetypes.StringValue `tfsdk:"thanos_query_memory_limit"`
+	ThanosStoreMemoryLimit       basetypes.StringValue `tfsdk:"thanos_store_memory_limit"`
+}
+
+// ObservabilityData returns the observability configuration with defaults applied to unset attributes.
+func (cc *ClusterConfiguration) ObservabilityData(ctx context.Context) (Observability, diag.Diagnostics) {
+	var o Observability
+	var d diag.Diagnostics
+	if !(cc.Observability.IsNull() || cc.Observability.IsUnknown()) {
+		d = cc.Observability.As(ctx, &o, basetypes.ObjectAsOptions{})
+	}
+
+	defaults := []struct {
+		value *basetypes.StringValue
+		def   string
+	}{
+		{&o.PrometheusLocalTsdbRetention, "5d"},
+		{&o.PrometheusMemoryLimit, "4Gi"},
+		{&o.PrometheusPvcStorageSize, "300Gi"},
+		{&o.ThanosQueryMemoryLimit, "1.2Gi"},
+		{&o.ThanosStoreMemoryLimit, "1.2Gi"},
+	}
+	for _, v := range defaults {
+		if v.value.IsNull() || v.value.IsUnknown() {
+			*v.value = basetypes.NewStringValue(v.def)
+		}
+	}
+	return o, d
+}
+
+// TagsData returns the resource level tags.
+func (d *AWSDataplane) TagsData(ctx context.Context) (map[string]string, diag.Diagnostics) {
+	tags := map[string]string{}
+	if d.Tags.IsNull() || d.Tags.IsUnknown() {
+		return tags, nil
+	}
+	diag := d.Tags.ElementsAs(ctx, &tags, false)
+	return tags, diag
 }
 
 func (d *AWSDataplane) AssumeRoleData(ctx context.Context) (AssumeRole, diag.Diagnostics) {
@@ -141,7 +362,164 @@ func (d *AWSDataplane) ClusterConfigurationData(ctx context.Context) (ClusterCon
 	diag := d.ClusterConfiguration.As(ctx, &cc, basetypes.ObjectAsOptions{})
 
 	if cc.Stack.IsNull() || cc.Stack.IsUnknown() {
-		cc.Stack = basetypes.NewStringValue("prod")
+		cc.Stack = basetypes.NewStringValue(DefaultStack)
+	}
+
+	// ds_region resolves to: the configured value, then the assume role region. If
+	// both are unset it is left empty and callers fall back to the region of the
+	// resolved AWS config. serde_bucket_region resolves to ds_region.
+	if cc.DsRegion.IsNull() || cc.DsRegion.IsUnknown() {
+		cc.DsRegion = basetypes.NewStringNull()
+		if !(d.AssumeRole.IsNull() || d.AssumeRole.IsUnknown()) {
+			ar, dg := d.AssumeRoleData(ctx)
+			diag.Append(dg...)
+			if !(ar.Region.IsNull() || ar.Region.IsUnknown()) && ar.Region.ValueString() != "" {
+				cc.DsRegion = basetypes.NewStringValue(ar.Region.ValueString())
+			}
+		}
+	}
+	if cc.SerdeBucketRegion.IsNull() || cc.SerdeBucketRegion.IsUnknown() {
+		cc.SerdeBucketRegion = cc.DsRegion
+	}
+
+	if cc.LoadBalancerClass.IsNull() || cc.LoadBalancerClass.IsUnknown() {
+		cc.LoadBalancerClass = basetypes.NewStringValue("service.k8s.aws/nlb")
+	}
+
+	if cc.NetworkMode.IsNull() || cc.NetworkMode.IsUnknown() {
+		cc.NetworkMode = basetypes.NewStringValue(NetworkModeCiliumEni)
+	}
+	if cc.CiliumPolicyAuditMode.IsNull() || cc.CiliumPolicyAuditMode.IsUnknown() {
+		cc.CiliumPolicyAuditMode = basetypes.NewBoolValue(false)
+	}
+
+	if cc.CiliumPolicyEnforcementMode.IsNull() || cc.CiliumPolicyEnforcementMode.IsUnknown() {
+		cc.CiliumPolicyEnforcementMode = basetypes.NewStringValue("always")
+	}
+
+	if cc.CiliumChartRepository.IsNull() || cc.CiliumChartRepository.IsUnknown() {
+		cc.CiliumChartRepository = basetypes.NewStringValue("https://helm.cilium.io")
+	}
+
+	if cc.KafkaAuthMode.IsNull() || cc.KafkaAuthMode.IsUnknown() {
+		cc.KafkaAuthMode = basetypes.NewStringValue(KafkaAuthModeIam)
+	}
+
+	if cc.RdsAuthMode.IsNull() || cc.RdsAuthMode.IsUnknown() {
+		cc.RdsAuthMode = basetypes.NewStringValue(RdsAuthModePassword)
+	}
+
+	if cc.StrictTrustPolicy.IsNull() || cc.StrictTrustPolicy.IsUnknown() {
+		cc.StrictTrustPolicy = basetypes.NewBoolValue(false)
+	}
+
+	if cc.ManageAccessEntry.IsNull() || cc.ManageAccessEntry.IsUnknown() {
+		cc.ManageAccessEntry = basetypes.NewBoolValue(false)
+	}
+	if cc.ClusterActiveTimeout.IsNull() || cc.ClusterActiveTimeout.IsUnknown() {
+		cc.ClusterActiveTimeout = basetypes.NewStringValue("0s")
+	}
+
+	if cc.IpFamily.IsNull() || cc.IpFamily.IsUnknown() {
+		cc.IpFamily = basetypes.NewStringValue(IpFamilyIpv4)
+	}
+
+	if cc.RdsPort.IsNull() || cc.RdsPort.IsUnknown() {
+		cc.RdsPort = basetypes.NewInt64Value(5432)
+	}
+
+	if cc.ValidateRdsConnectivity.IsNull() || cc.ValidateRdsConnectivity.IsUnknown() {
+		cc.ValidateRdsConnectivity = basetypes.NewBoolValue(false)
+	}
+
+	if cc.ValidateRdsTls.IsNull() || cc.ValidateRdsTls.IsUnknown() {
+		cc.ValidateRdsTls = basetypes.NewBoolValue(false)
+	}
+
+	if cc.ValidateDns.IsNull() || cc.ValidateDns.IsUnknown() {
+		cc.ValidateDns = basetypes.NewBoolValue(false)
+	}
+
+	if cc.ManageInterruptionQueue.IsNull() || cc.ManageInterruptionQueue.IsUnknown() {
+		cc.ManageInterruptionQueue = basetypes.NewBoolValue(false)
+	}
+
+	if cc.NthCordonOnly.IsNull() || cc.NthCordonOnly.IsUnknown() {
+		cc.NthCordonOnly = basetypes.NewBoolValue(false)
+	}
+
+	if cc.CiliumNodesReadyTimeout.IsNull() || cc.CiliumNodesReadyTimeout.IsUnknown() {
+		cc.CiliumNodesReadyTimeout = basetypes.NewStringValue("5m")
+	}
+
+	if cc.CustomCredentialsRolloutTimeout.IsNull() || cc.CustomCredentialsRolloutTimeout.IsUnknown() {
+		cc.CustomCredentialsRolloutTimeout = basetypes.NewStringValue("10m")
+	}
+
+	if cc.CiliumInstallTimeout.IsNull() || cc.CiliumInstallTimeout.IsUnknown() {
+		cc.CiliumInstallTimeout = basetypes.NewStringValue("10m")
+	}
+
+	if cc.NodeClaimDrainTimeout.IsNull() || cc.NodeClaimDrainTimeout.IsUnknown() {
+		cc.NodeClaimDrainTimeout = basetypes.NewStringValue("20m")
+	}
+
+	if cc.PodEvictionGracePeriod.IsNull() || cc.PodEvictionGracePeriod.IsUnknown() {
+		cc.PodEvictionGracePeriod = basetypes.NewStringValue("5m")
+	}
+
+	if cc.SecretDeletionRecoveryWindowDays.IsNull() || cc.SecretDeletionRecoveryWindowDays.IsUnknown() {
+		cc.SecretDeletionRecoveryWindowDays = basetypes.NewInt64Value(0)
+	}
+
+	if cc.RetainSecretsOnDestroy.IsNull() || cc.RetainSecretsOnDestroy.IsUnknown() {
+		cc.RetainSecretsOnDestroy = basetypes.NewBoolValue(false)
+	}
+	if cc.SkipLoadBalancerCleanup.IsNull() || cc.SkipLoadBalancerCleanup.IsUnknown() {
+		cc.SkipLoadBalancerCleanup = basetypes.NewBoolValue(false)
+	}
+	if cc.ForceDestroy.IsNull() || cc.ForceDestroy.IsUnknown() {
+		cc.ForceDestroy = basetypes.NewBoolValue(false)
+	}
+	if cc.DeletionMode.IsNull() || cc.DeletionMode.IsUnknown() {
+		cc.DeletionMode = basetypes.NewStringValue(DeletionModeDestroy)
+	}
+	if cc.CreateEcrRepositories.IsNull() || cc.CreateEcrRepositories.IsUnknown() {
+		cc.CreateEcrRepositories = basetypes.NewBoolValue(true)
+	}
+	if cc.ClusterSettingsBackupCount.IsNull() || cc.ClusterSettingsBackupCount.IsUnknown() {
+		cc.ClusterSettingsBackupCount = basetypes.NewInt64Value(3)
+	}
+	if cc.ImageCopyConcurrency.IsNull() || cc.ImageCopyConcurrency.IsUnknown() {
+		cc.ImageCopyConcurrency = basetypes.NewInt64Value(3)
+	}
+	if cc.ImageReplicaBestEffort.IsNull() || cc.ImageReplicaBestEffort.IsUnknown() {
+		cc.ImageReplicaBestEffort = basetypes.NewBoolValue(false)
+	}
+	if cc.ImageDeliveryMode.IsNull() || cc.ImageDeliveryMode.IsUnknown() {
+		cc.ImageDeliveryMode = basetypes.NewStringValue(ImageDeliveryModeCopy)
+	}
+	if cc.ImageSignaturePolicy.IsNull() || cc.ImageSignaturePolicy.IsUnknown() {
+		cc.ImageSignaturePolicy = basetypes.NewStringValue(ImageSignaturePolicyAcceptAnything)
+	}
+	if cc.CrdEstablishedTimeout.IsNull() || cc.CrdEstablishedTimeout.IsUnknown() {
+		cc.CrdEstablishedTimeout = basetypes.NewStringValue("2m")
+	}
+	if cc.KustomizationReconcileTimeout.IsNull() || cc.KustomizationReconcileTimeout.IsUnknown() {
+		cc.KustomizationReconcileTimeout = basetypes.NewStringValue("30m")
+	}
+	if cc.WorkloadReadyTimeout.IsNull() || cc.WorkloadReadyTimeout.IsUnknown() {
+		cc.WorkloadReadyTimeout = basetypes.NewStringValue("10m")
+	}
+	if cc.OrderedUpgrade.IsNull() || cc.OrderedUpgrade.IsUnknown() {
+		cc.OrderedUpgrade = basetypes.NewBoolValue(false)
+	}
+
+	if cc.ClusterConfigNamespace.IsNull() || cc.ClusterConfigNamespace.IsUnknown() {
+		cc.ClusterConfigNamespace = basetypes.NewStringValue(DefaultClusterConfigNamespace)
+	}
+	if cc.DeltaStreamNamespace.IsNull() || cc.DeltaStreamNamespace.IsUnknown() {
+		cc.DeltaStreamNamespace = basetypes.NewStringValue(DefaultDeltaStreamNamespace)
 	}
 
 	return cc, diag
@@ -149,6 +527,7 @@ func (d *AWSDataplane) ClusterConfigurationData(ctx context.Context) (ClusterCon
 
 var Schema = schema.Schema{
 	MarkdownDescription: "AWS Dataplane resource",
+	Version:             1,
 
 	Attributes: map[string]schema.Attribute{
 		"assume_role": schema.SingleNestedAttribute{
@@ -159,13 +538,31 @@ var Schema = schema.Schema{
 					Description: "Amazon Resource Name (ARN) of an IAM Role to assume prior to making API calls.",
 					Optional:    true,
 				},
+				"via_role_arns": schema.ListAttribute{
+					Description: "Ordered list of intermediary IAM Role ARNs to assume, each with the credentials of the previous one, before assuming role_arn. AWS limits chained role sessions to one hour.",
+					ElementType: basetypes.StringType{},
+					Optional:    true,
+				},
 				"session_name": schema.StringAttribute{
-					Description: "An identifier for the assumed role session.",
+					Description: "An identifier for the assumed role session. Defaults to deltastream-dp-<infra_id> so that API calls can be attributed to the dataplane.",
 					Optional:    true,
+					Validators:  []validator.String{SessionName()},
 				},
 				"region": schema.StringAttribute{
 					Description: "The AWS region to use for the assume role.",
 					Optional:    true,
+					Validators:  []validator.String{Region()},
+				},
+				"session_tags": schema.MapAttribute{
+					Description: "Session tags to pass when assuming the role, for use in attribute-based access control policies.",
+					ElementType: basetypes.StringType{},
+					Optional:    true,
+					Validators:  []validator.Map{SessionTags()},
+				},
+				"source_identity": schema.StringAttribute{
+					Description: "The source identity to set on the assumed role session, recorded in CloudTrail.",
+					Optional:    true,
+					Validators:  []validator.String{SourceIdentity()},
 				},
 			},
 		},
@@ -176,40 +573,57 @@ var Schema = schema.Schema{
 				"stack": schema.StringAttribute{
 					Description: "The type of DeltaStream dataplane (default: prod).",
 					Optional:    true,
+					Validators:  []validator.String{stringvalidator.OneOf(Stacks...)},
 				},
 				"ds_account_id": schema.StringAttribute{
 					Description: "The account ID provided by DeltaStream.",
 					Required:    true,
 				},
 				"ds_region": schema.StringAttribute{
-					Description: "The AWS region provided by DeltaStream.",
+					Description: "The AWS region provided by DeltaStream (default: assume_role.region, then the provider's AWS region).",
 					Optional:    true,
+					Validators:  []validator.String{Region()},
 				},
 
 				"account_id": schema.StringAttribute{
-					Description: "The account ID hosting the DeltaStream dataplane.",
-					Required:    true,
+					Description:   "The account ID hosting the DeltaStream dataplane.",
+					Required:      true,
+					PlanModifiers: []planmodifier.String{stringplanmodifier.RequiresReplace()},
 				},
 				"infra_id": schema.StringAttribute{
-					Description: "The infra ID of the DeltaStream dataplane (provided by DeltaStream).",
-					Required:    true,
+					Description:   "The infra ID of the DeltaStream dataplane (provided by DeltaStream).",
+					Required:      true,
+					PlanModifiers: []planmodifier.String{stringplanmodifier.RequiresReplace()},
 				},
 				"eks_resource_id": schema.StringAttribute{
-					Description: "The resource ID of the DeltaStream dataplane (provided by DeltaStream).",
-					Required:    true,
+					Description:   "The resource ID of the DeltaStream dataplane (provided by DeltaStream).",
+					Required:      true,
+					PlanModifiers: []planmodifier.String{stringplanmodifier.RequiresReplace()},
 				},
 				"cluster_index": schema.Int64Attribute{
-					Description: "The index of the cluster (provided by DeltaStream).",
-					Optional:    true,
+					Description:   "The index of the cluster (provided by DeltaStream).",
+					Optional:      true,
+					PlanModifiers: []planmodifier.Int64{int64planmodifier.RequiresReplace()},
 				},
 				"product_version": schema.StringAttribute{
 					Description: "The version of the DeltaStream product. (provided by DeltaStream)",
 					Required:    true,
 				},
+				"manifests_source_url": schema.StringAttribute{
+					Description: "The OCI repository prefix the Flux sources of the platform and data plane manifests are pulled from, the infra and data-plane artifacts are expected below it. Set it to pull mirrored manifests (default: oci://<account_id>.dkr.ecr.<region>.amazonaws.com/deltastreaminc/oci).",
+					Optional:    true,
+					Validators:  []validator.String{stringvalidator.RegexMatches(regexp.MustCompile(`^oci://[a-zA-Z0-9-\.]+(:[0-9]+)?(/[a-z0-9._/-]*[a-z0-9])?$`), "Invalid OCI repository URL")},
+				},
+				"manifests_source_ref": schema.StringAttribute{
+					Description: "The tag of the platform and data plane manifests artifacts (default: product_version).",
+					Optional:    true,
+					Validators:  []validator.String{stringvalidator.RegexMatches(regexp.MustCompile(`^[a-zA-Z0-9_][a-zA-Z0-9_.-]{0,127}$`), "Invalid tag")},
+				},
 
 				"vpc_id": schema.StringAttribute{
-					Description: "The VPC ID of the cluster.",
-					Required:    true,
+					Description:   "The VPC ID of the cluster.",
+					Required:      true,
+					PlanModifiers: []planmodifier.String{stringplanmodifier.RequiresReplace()},
 				},
 				"vpc_cidr": schema.StringAttribute{
 					Description: "The CIDR of the VPC.",
@@ -220,11 +634,35 @@ var Schema = schema.Schema{
 					Required:    true,
 					Validators:  []validator.String{},
 				},
+				"vpc_ipv6_cidr": schema.StringAttribute{
+					Description: "The IPv6 CIDR of the VPC. Required when ip_family is dualstack.",
+					Optional:    true,
+					Validators:  []validator.String{Ipv6Cidr()},
+				},
+				"ip_family": schema.StringAttribute{
+					Description: "The IP family of the cluster network, one of ipv4 or dualstack (default: ipv4).",
+					Optional:    true,
+					Validators:  []validator.String{stringvalidator.OneOf(IpFamilyIpv4, IpFamilyDualStack)},
+				},
 				"private_link_subnets_ids": schema.ListAttribute{
 					Description: "The private subnet IDs of the private links from dataplane VPC.",
 					ElementType: basetypes.StringType{},
 					Required:    true,
 				},
+				"kube_api_endpoint_override": schema.StringAttribute{
+					Description: "The URL used to reach the EKS API server instead of the cluster endpoint, e.g. when the cluster endpoint is private and reached through a peered network or bastion.",
+					Optional:    true,
+					Validators:  []validator.String{stringvalidator.RegexMatches(regexp.MustCompile(`^https://.+$`), "Invalid kube API endpoint")},
+				},
+				"cluster_active_timeout": schema.StringAttribute{
+					Description: "The maximum time to wait for an EKS cluster that is being created or updated to become ACTIVE before connecting to it (default: 0s, fail immediately).",
+					Optional:    true,
+					Validators:  []validator.String{Duration()},
+				},
+				"manage_access_entry": schema.BoolAttribute{
+					Description: "Create an EKS access entry with cluster admin access for the assumed role (or the caller when no role is assumed) before connecting to the cluster. Requires the API or API_AND_CONFIG_MAP cluster authentication mode (default: false).",
+					Optional:    true,
+				},
 
 				"private_subnet_ids": schema.ListAttribute{
 					Description: "The private subnet IDs hosting nodes for this cluster.",
@@ -238,13 +676,18 @@ var Schema = schema.Schema{
 					Required:    true,
 				},
 				"metrics_url": schema.StringAttribute{
-					Description: "The URL to push metrics.",
+					Description: "The https URL to push metrics.",
 					Required:    true,
+					Validators:  []validator.String{stringvalidator.RegexMatches(regexp.MustCompile(`^https://[a-zA-Z0-9-\.]+\.[a-zA-Z]{2,}(/.*)?$`), "Invalid metrics URL, must be an https URL without a port")},
 				},
 				"interruption_queue_name": schema.StringAttribute{
 					Description: "The name of the SQS queue for handling interruption events.",
 					Required:    true,
 				},
+				"manage_interruption_queue": schema.BoolAttribute{
+					Description: "Create the interruption queue, and the EventBridge rules forwarding spot interruption, rebalance recommendation, scheduled change and instance state change events to it, when they do not exist (default: false). When false the queue must already exist. The queue and rules are kept when the dataplane is destroyed.",
+					Optional:    true,
+				},
 				"product_artifacts_bucket": schema.StringAttribute{
 					Description: "The S3 bucket for storing DeltaStream product artifacts.",
 					Required:    true,
@@ -253,6 +696,11 @@ var Schema = schema.Schema{
 					Description: "The S3 bucket for storing SERDE artifacts.",
 					Required:    true,
 				},
+				"serde_bucket_region": schema.StringAttribute{
+					Description: "The AWS region of the SERDE bucket (default: ds_region).",
+					Optional:    true,
+					Validators:  []validator.String{Region()},
+				},
 				"workload_state_bucket": schema.StringAttribute{
 					Description: "The S3 bucket for storing workload state.",
 					Required:    true,
@@ -261,6 +709,11 @@ var Schema = schema.Schema{
 					Description: "The S3 bucket for storing observability data.",
 					Required:    true,
 				},
+				"o11y_bucket_region": schema.StringAttribute{
+					Description: "The AWS region of the observability bucket, when observability storage is centralized outside the dataplane region (default: the dataplane region).",
+					Optional:    true,
+					Validators:  []validator.String{Region()},
+				},
 
 				"aws_secrets_manager_ro_role_arn": schema.StringAttribute{
 					Description: "The ARN of the role to assume for reading secrets from AWS secrets manager.",
@@ -332,8 +785,8 @@ var Schema = schema.Schema{
 					Validators:  []validator.String{stringvalidator.RegexMatches(regexp.MustCompile(`^arn:aws:iam::[0-9]{12}:role/.+$`), "Invalid Role ARN")},
 				},
 				"cw2loki_role_arn": schema.StringAttribute{
-					Description: "The ARN of the role to assume for managing CloudWatch-Loki resources.",
-					Required:    true,
+					Description: "The ARN of the role to assume for managing CloudWatch-Loki resources. Required when cw2loki_sqs_url is set.",
+					Optional:    true,
 					Validators:  []validator.String{stringvalidator.RegexMatches(regexp.MustCompile(`^arn:aws:iam::[0-9]{12}:role/.+$`), "Invalid Role ARN")},
 				},
 				"ecr_readonly_role_arn": schema.StringAttribute{
@@ -357,21 +810,40 @@ var Schema = schema.Schema{
 					Validators:  []validator.String{stringvalidator.RegexMatches(regexp.MustCompile(`^arn:aws:iam::[0-9]{12}:role/.+$`), "Invalid Role ARN")},
 				},
 				"kafka_role_arn": schema.StringAttribute{
-					Description: "The ARN of the role to assume for interacting with Kafka topcis and data.",
-					Required:    true,
+					Description: "The ARN of the role to assume for interacting with Kafka topcis and data. Required when kafka_auth_mode is iam.",
+					Optional:    true,
 					Validators:  []validator.String{stringvalidator.RegexMatches(regexp.MustCompile(`^arn:aws:iam::[0-9]{12}:role/.+$`), "Invalid Role ARN")},
 				},
 				"kafka_role_external_id": schema.StringAttribute{
-					Description: "The external ID for the kafka role.",
-					Required:    true,
+					Description: "The external ID for the kafka role. Required when kafka_auth_mode is iam.",
+					Optional:    true,
 				},
 				"aws_load_balancer_controller_role_arn": schema.StringAttribute{
 					Description: "The ARN of the role to assume for managing AWS Load Balancer resources.",
 					Required:    true,
 				},
+				"datagen_role_arn": schema.StringAttribute{
+					Description: "The ARN of the role to assume for the data generator.",
+					Optional:    true,
+					Validators:  []validator.String{stringvalidator.RegexMatches(regexp.MustCompile(`^arn:aws:iam::[0-9]{12}:role/.+$`), "Invalid Role ARN")},
+				},
+				"nth_role_arn": schema.StringAttribute{
+					Description: "The ARN of the role to assume for the node termination handler.",
+					Optional:    true,
+					Validators:  []validator.String{stringvalidator.RegexMatches(regexp.MustCompile(`^arn:aws:iam::[0-9]{12}:role/.+$`), "Invalid Role ARN")},
+				},
+				"nth_cordon_only": schema.BoolAttribute{
+					Description: "Only cordon nodes, without draining them, when handling interruptions with the node termination handler (default: false).",
+					Optional:    true,
+				},
+				"default_instance_profile": schema.StringAttribute{
+					Description: "The name of the default instance profile for nodes.",
+					Optional:    true,
+					Validators:  []validator.String{stringvalidator.RegexMatches(regexp.MustCompile(`^[\w+=,.@-]{1,128}$`), "Invalid instance profile name")},
+				},
 
 				"workload_credentials_mode": schema.StringAttribute{
-					Description: "The mode for managing workload credentials.",
+					Description: "The mode for managing workload credentials. Changing the mode of an existing dataplane restarts the dp-operator and the DeltaStream services once the new configuration is reconciled, so that they authenticate with the new mode. The credentials of the previous mode must remain valid until the apply completes.",
 					Required:    true,
 					Validators:  []validator.String{stringvalidator.OneOf("secret", "iamrole")},
 				},
@@ -396,9 +868,9 @@ var Schema = schema.Schema{
 					Validators:  []validator.String{stringvalidator.RegexMatches(regexp.MustCompile(`^[a-zA-Z0-9-\.]+\.[a-zA-Z]{2,}$`), "Invalid hostname")},
 				},
 				"o11y_ingress_security_groups": schema.StringAttribute{
-					Description: "Comma separated AWS security group name(s) that will be attached to obervability endpoint load balancer.",
+					Description: "Comma separated AWS security group ID(s) (sg-xxxxxxxx) and/or name(s) that will be attached to obervability endpoint load balancer. Names are resolved to IDs by the AWS Load Balancer Controller.",
 					Optional:    true,
-					Validators:  []validator.String{stringvalidator.RegexMatches(regexp.MustCompile(`^[a-zA-Z0-9-,]+$`), "Invalid o11y ingress security group names")},
+					Validators:  []validator.String{SecurityGroups()},
 				},
 				"o11y_subnet_mode": schema.StringAttribute{
 					Description: "The subnet mode for observability endpoint.",
@@ -415,6 +887,11 @@ var Schema = schema.Schema{
 					Optional:    true,
 					Validators:  []validator.String{stringvalidator.RegexMatches(regexp.MustCompile(`^arn:aws:acm:.+:[0-9]{12}:certificate/.+$`), "Invalid Certificate ARN")},
 				},
+				"o11y_acme_email": schema.StringAttribute{
+					Description: "The email address used to register with the ACME server for the observability endpoint. Required when o11y_tls_mode is acme.",
+					Optional:    true,
+					Validators:  []validator.String{stringvalidator.RegexMatches(regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[^@\s]+$`), "Invalid email address")},
+				},
 
 				"custom_credentials_role_arn": schema.StringAttribute{
 					Description: "The ARN of the role to assume for use by the custom credentials plugin.",
@@ -425,16 +902,29 @@ var Schema = schema.Schema{
 					Description: "The image to use for the custom credentials plugin.",
 					Optional:    true,
 				},
+				"custom_credentials_rollout_timeout": schema.StringAttribute{
+					Description: "The maximum time to wait for dp-manager to roll out after deploying the custom credentials plugin (default: 10m).",
+					Optional:    true,
+					Validators:  []validator.String{Duration()},
+				},
+				"strict_trust_policy": schema.BoolAttribute{
+					Description: "Fail instead of warning when a role trust policy about to be replaced trusts a different service account or cluster (default: false).",
+					Optional:    true,
+				},
 
 				"api_hostname": schema.StringAttribute{
 					Description: "The hostname of the dataplane API endpoint.",
 					Required:    true,
 					Validators:  []validator.String{stringvalidator.RegexMatches(regexp.MustCompile(`^[a-zA-Z0-9-\.]+\.[a-zA-Z]{2,}$`), "Invalid hostname")},
 				},
+				"validate_dns": schema.BoolAttribute{
+					Description: "Check during preflight that the parent zones of o11y_hostname and api_hostname are Route53 hosted zones of the account, delegated in public DNS, without existing records for the hostnames. Problems are reported as warnings (default: false).",
+					Optional:    true,
+				},
 				"api_ingress_security_groups": schema.StringAttribute{
-					Description: "Comma separated AWS security group name(s) that will be attached to API endpoint load balancer.",
+					Description: "Comma separated AWS security group ID(s) (sg-xxxxxxxx) and/or name(s) that will be attached to API endpoint load balancer. Names are resolved to IDs by the AWS Load Balancer Controller.",
 					Optional:    true,
-					Validators:  []validator.String{stringvalidator.RegexMatches(regexp.MustCompile(`^[a-zA-Z0-9-,]+$`), "Invalid api ingress security group names")},
+					Validators:  []validator.String{SecurityGroups()},
 				},
 				"api_subnet_mode": schema.StringAttribute{
 					Description: "The subnet mode for dataplane API endpoint.",
@@ -451,6 +941,95 @@ var Schema = schema.Schema{
 					Optional:    true,
 					Validators:  []validator.String{stringvalidator.RegexMatches(regexp.MustCompile(`^arn:aws:acm:.+:[0-9]{12}:certificate/.+$`), "Invalid Certificate ARN")},
 				},
+				"api_acme_email": schema.StringAttribute{
+					Description: "The email address used to register with the ACME server for the dataplane API endpoint. Required when api_tls_mode is acme.",
+					Optional:    true,
+					Validators:  []validator.String{stringvalidator.RegexMatches(regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[^@\s]+$`), "Invalid email address")},
+				},
+				"loadbalancer_class": schema.StringAttribute{
+					Description: "The load balancer class used for the dataplane API and observability endpoints (default: service.k8s.aws/nlb).",
+					Optional:    true,
+					Validators:  []validator.String{stringvalidator.LengthAtLeast(1)},
+				},
+				"rds_auth_mode": schema.StringAttribute{
+					Description: "How the platform authenticates to RDS, one of password or iam. In iam mode no database password is read or stored, the platform generates short-lived tokens with rds_iam_role_arn and connects using rds_host, rds_port, rds_database and rds_username (default: password).",
+					Optional:    true,
+					Validators:  []validator.String{stringvalidator.OneOf(RdsAuthModePassword, RdsAuthModeIam)},
+				},
+				"rds_iam_role_arn": schema.StringAttribute{
+					Description: "The ARN of the role used to generate RDS IAM authentication tokens. Required when rds_auth_mode is iam.",
+					Optional:    true,
+					Validators:  []validator.String{stringvalidator.RegexMatches(regexp.MustCompile(`^arn:aws:iam::[0-9]{12}:role/.+$`), "Invalid Role ARN")},
+				},
+				"rds_host": schema.StringAttribute{
+					Description: "The RDS endpoint hostname. Required when rds_auth_mode is iam.",
+					Optional:    true,
+				},
+				"rds_port": schema.Int64Attribute{
+					Description: "The RDS endpoint port used when rds_auth_mode is iam (default: 5432).",
+					Optional:    true,
+					Validators:  []validator.Int64{int64validator.Between(1, 65535)},
+				},
+				"rds_database": schema.StringAttribute{
+					Description: "The database name. Required when rds_auth_mode is iam.",
+					Optional:    true,
+				},
+				"rds_username": schema.StringAttribute{
+					Description: "The database user enabled for IAM authentication. Required when rds_auth_mode is iam.",
+					Optional:    true,
+				},
+				"validate_rds_connectivity": schema.BoolAttribute{
+					Description: "Open a TCP connection to the RDS instance from the Terraform host before configuring the dataplane. Leave disabled when the instance is only reachable from inside the VPC (default: false).",
+					Optional:    true,
+				},
+				"validate_rds_tls": schema.BoolAttribute{
+					Description: "Also negotiate TLS with the RDS instance when validate_rds_connectivity is enabled (default: false).",
+					Optional:    true,
+				},
+				"network_mode": schema.StringAttribute{
+					Description: "How pod networking is provided. cilium-eni removes the AWS VPC CNI and lets Cilium manage ENIs, aws-cni-chaining keeps the AWS VPC CNI and chains Cilium to it. Changing the mode replaces the dataplane (default: cilium-eni).",
+					Optional:    true,
+					Validators:  []validator.String{stringvalidator.OneOf(NetworkModeCiliumEni, NetworkModeAwsCniChaining)},
+					PlanModifiers: []planmodifier.String{stringplanmodifier.RequiresReplaceIf(func(ctx context.Context, req planmodifier.StringRequest, resp *stringplanmodifier.RequiresReplaceIfFuncResponse) {
+						// unset is the same as the default mode
+						effective := func(v basetypes.StringValue) string {
+							if v.IsNull() {
+								return NetworkModeCiliumEni
+							}
+							return v.ValueString()
+						}
+						resp.RequiresReplace = effective(req.StateValue) != effective(req.PlanValue)
+					}, "Changing the network mode replaces the dataplane.", "Changing the network mode replaces the dataplane.")},
+				},
+				"cilium_policy_audit_mode": schema.BoolAttribute{
+					Description: "Enable Cilium policy audit mode, logging policy denials instead of dropping traffic (default: false).",
+					Optional:    true,
+				},
+				"cilium_policy_enforcement_mode": schema.StringAttribute{
+					Description: "The Cilium policy enforcement mode (default: always).",
+					Optional:    true,
+					Validators:  []validator.String{stringvalidator.OneOf("default", "always", "never")},
+				},
+				"cilium_version": schema.StringAttribute{
+					Description: "The version of the Cilium helm chart to install. When unset the chart bundled with the provider is used. Changing the version upgrades the release in place.",
+					Optional:    true,
+					Validators:  []validator.String{stringvalidator.RegexMatches(regexp.MustCompile(`^v?[0-9]+\.[0-9]+\.[0-9]+(-[0-9A-Za-z.-]+)?$`), "Invalid semantic version")},
+				},
+				"cilium_chart_repository": schema.StringAttribute{
+					Description: "The helm repository to download the Cilium chart from when cilium_version is set (default: https://helm.cilium.io).",
+					Optional:    true,
+					Validators:  []validator.String{stringvalidator.RegexMatches(regexp.MustCompile(`^https?://.+$`), "Invalid helm repository URL")},
+				},
+				"cilium_nodes_ready_timeout": schema.StringAttribute{
+					Description: "The maximum time to wait for nodes to become ready after installing Cilium (default: 5m).",
+					Optional:    true,
+					Validators:  []validator.String{Duration()},
+				},
+				"cilium_install_timeout": schema.StringAttribute{
+					Description: "The maximum time to wait for the Cilium helm release to install or upgrade. A failed install or upgrade is rolled back (default: 10m).",
+					Optional:    true,
+					Validators:  []validator.String{Duration()},
+				},
 
 				"kms_key_id": schema.StringAttribute{
 					Description: "The KMS key ID for encrypting credentials store in the dataplane vault.",
@@ -475,25 +1054,40 @@ var Schema = schema.Schema{
 					Description: "The name of the kafka cluster.",
 					Required:    true,
 				},
+				"kafka_auth_mode": schema.StringAttribute{
+					Description: "The authentication mode for the kafka cluster, one of iam or scram (default: iam).",
+					Optional:    true,
+					Validators:  []validator.String{stringvalidator.OneOf(KafkaAuthModeIam, KafkaAuthModeScram)},
+				},
+				"kafka_scram_secret": schema.StringAttribute{
+					Description: "The name or ARN of the AWS Secrets Manager secret holding the SASL/SCRAM username and password for the kafka cluster. Required when kafka_auth_mode is scram.",
+					Optional:    true,
+				},
 
 				"rds_resource_id": schema.StringAttribute{
 					Description: "The resource ID of the RDS instance for storing DeltaStream data.",
 					Required:    true,
 				},
+				"rds_credentials_secret_id": schema.StringAttribute{
+					Description: "The name or ARN of the AWS Secrets Manager secret holding the RDS credentials. Defaults to the secret created for the RDS instance identified by rds_resource_id.",
+					Optional:    true,
+					Validators:  []validator.String{stringvalidator.LengthAtLeast(1)},
+				},
 				"cw2loki_sqs_url": schema.StringAttribute{
-					Description: "The SQS URL for ingesting CloudWatch data into observability tools.",
-					Required:    true,
+					Description: "The SQS URL for ingesting CloudWatch data into observability tools. CloudWatch ingestion is disabled when not set.",
+					Optional:    true,
+					Validators:  []validator.String{stringvalidator.RegexMatches(regexp.MustCompile(`^https://.+$`), "Invalid SQS URL")},
 				},
 
 				"cp_kafka_hosts": schema.ListAttribute{
-					Description: "The list of kafka brokers for control plane connectivity.",
+					Description: "The list of kafka brokers for control plane connectivity. When empty the dataplane is not connected to a control plane kafka.",
 					ElementType: basetypes.StringType{},
-					Required:    true,
+					Optional:    true,
 				},
 				"cp_kafka_listener_ports": schema.ListAttribute{
 					Description: "The list of kafka listener ports for control plane connectivity.",
 					ElementType: basetypes.StringType{},
-					Required:    true,
+					Optional:    true,
 				},
 
 				"console_hostname": schema.StringAttribute{
@@ -510,8 +1104,170 @@ var Schema = schema.Schema{
 					Description: "Installation timestamp provided by caller.",
 					Required:    true,
 				},
+
+				"node_claim_drain_timeout": schema.StringAttribute{
+					Description: "The maximum time to wait for Karpenter node claims to drain when destroying the dataplane (default: 20m).",
+					Optional:    true,
+					Validators:  []validator.String{Duration()},
+				},
+				"pod_eviction_grace_period": schema.StringAttribute{
+					Description: "The time to keep retrying the eviction of a pod blocked by a PodDisruptionBudget before force deleting it when destroying the dataplane (default: 5m).",
+					Optional:    true,
+					Validators:  []validator.String{Duration()},
+				},
+				"secret_deletion_recovery_window_days": schema.Int64Attribute{
+					Description: "The number of days AWS secrets manager retains the deployment config secret after destroy. 0 deletes the secret without recovery, otherwise must be between 7 and 30 (default: 0).",
+					Optional:    true,
+					Validators:  []validator.Int64{int64validator.Any(int64validator.OneOf(0), int64validator.Between(7, 30))},
+				},
+				"cluster_settings_backup_count": schema.Int64Attribute{
+					Description: "The number of backups of the cluster settings secret to retain. A backup is taken before the settings are changed, 0 disables backups (default: 3).",
+					Optional:    true,
+					Validators:  []validator.Int64{int64validator.AtLeast(0)},
+				},
+				"retain_secrets_on_destroy": schema.BoolAttribute{
+					Description: "Skip deleting the deployment config secret when destroying the dataplane (default: false).",
+					Optional:    true,
+				},
+				"skip_loadbalancer_cleanup": schema.BoolAttribute{
+					Description: "Skip deleting the istio LoadBalancer services when destroying the dataplane, e.g. when the NLBs are managed externally (default: false).",
+					Optional:    true,
+				},
+				"force_destroy": schema.BoolAttribute{
+					Description: "Skip all in-cluster cleanup when destroying the dataplane and only remove the deployment config secret. Use when the cluster is already broken or unreachable (default: false).",
+					Optional:    true,
+				},
+				"deletion_mode": schema.StringAttribute{
+					Description: "What destroying the resource does. destroy uninstalls DeltaStream from the cluster, release leaves the cluster workloads running and only removes the deployment config secret, e.g. when handing the cluster over (default: destroy).",
+					Optional:    true,
+					Validators:  []validator.String{stringvalidator.OneOf(DeletionModeDestroy, DeletionModeRelease)},
+				},
+				"create_ecr_repositories": schema.BoolAttribute{
+					Description: "Create the destination ECR repositories before copying images. Disable if the repositories are pre-provisioned (default: true).",
+					Optional:    true,
+				},
+				"image_delivery_mode": schema.StringAttribute{
+					Description: "How product images are delivered to the dataplane account. copy copies every image into the dataplane ECR registry, pull_through configures an ECR pull-through cache rule that mirrors images from the DeltaStream registry on first pull (default: copy).",
+					Optional:    true,
+					Validators:  []validator.String{stringvalidator.OneOf(ImageDeliveryModeCopy, ImageDeliveryModePullThrough)},
+				},
+				"image_copy_concurrency": schema.Int64Attribute{
+					Description: "The number of images copied at the same time (default: 3).",
+					Optional:    true,
+					Validators:  []validator.Int64{int64validator.Between(1, 32)},
+				},
+				"image_copy_rate_limit": schema.StringAttribute{
+					Description: "The maximum number of bytes per second read from the source registry across all image copies, as a quantity (e.g. 20Mi). Unlimited when unset. Cannot be combined with the verify image signature policy.",
+					Optional:    true,
+					Validators:  []validator.String{Quantity()},
+				},
+				"image_replica_regions": schema.ListAttribute{
+					Description: "Additional regions whose ECR registry in the dataplane account receives a copy of the product images after the primary copy, e.g. for disaster recovery. Requires the copy image delivery mode.",
+					ElementType: basetypes.StringType{},
+					Optional:    true,
+					Validators: []validator.List{
+						listvalidator.UniqueValues(),
+						listvalidator.ValueStringsAre(Region()),
+					},
+				},
+				"image_replica_best_effort": schema.BoolAttribute{
+					Description: "Report image copy failures to replica regions as warnings instead of failing the apply (default: false).",
+					Optional:    true,
+				},
+				"image_signature_policy": schema.StringAttribute{
+					Description: "The signature policy applied when copying images. accept_anything copies images without checking signatures, verify requires every source image to carry a sigstore (cosign) signature made with image_signature_public_key and fails the copy otherwise. Requires the copy image delivery mode without an image_copy_rate_limit (default: accept_anything).",
+					Optional:    true,
+					Validators:  []validator.String{stringvalidator.OneOf(ImageSignaturePolicyAcceptAnything, ImageSignaturePolicyVerify)},
+				},
+				"image_signature_public_key": schema.StringAttribute{
+					Description: "The PEM encoded public key that product images are verified against when image_signature_policy is verify. This is the trust anchor of the verification: use the image signing public key published by DeltaStream, or the key of your own signing pipeline when copying re-signed images from a customer supplied image_list.",
+					Optional:    true,
+					Validators:  []validator.String{stringvalidator.RegexMatches(regexp.MustCompile(`^-----BEGIN PUBLIC KEY-----`), "Invalid PEM encoded public key")},
+				},
+				"crd_established_timeout": schema.StringAttribute{
+					Description: "The maximum time to wait for Flux CRDs to become established before applying Flux resources (default: 2m).",
+					Optional:    true,
+					Validators:  []validator.String{Duration()},
+				},
+				"kustomization_reconcile_timeout": schema.StringAttribute{
+					Description: "The maximum time to wait for Flux Kustomizations to become ready before failing with their reconcile errors (default: 30m).",
+					Optional:    true,
+					Validators:  []validator.String{Duration()},
+				},
+				"workload_ready_timeout": schema.StringAttribute{
+					Description: "The maximum time to wait, after the services are installed, for the deployments and statefulsets in the DeltaStream namespace to become ready. 0s skips the check (default: 10m).",
+					Optional:    true,
+					Validators:  []validator.String{Duration()},
+				},
+				"ordered_upgrade": schema.BoolAttribute{
+					Description: "Suspend the infra and data-plane Flux Kustomizations while new manifests are applied and resume them afterwards, so Flux reconciles the upgrade once instead of intermediate states (default: false).",
+					Optional:    true,
+				},
+				"cluster_config_namespace": schema.StringAttribute{
+					Description: "Namespace holding the cluster settings and Flux Kustomizations (default: cluster-config).",
+					Optional:    true,
+					Validators:  []validator.String{stringvalidator.RegexMatches(regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`), "Invalid namespace name"), stringvalidator.LengthAtMost(63)},
+				},
+				"deltastream_namespace": schema.StringAttribute{
+					Description: "Namespace hosting the DeltaStream services (default: deltastream).",
+					Optional:    true,
+					Validators:  []validator.String{stringvalidator.RegexMatches(regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`), "Invalid namespace name"), stringvalidator.LengthAtMost(63)},
+				},
+				"image_list": schema.SingleNestedAttribute{
+					Description: "Images to copy for the product version. When set the image list is not fetched from the DeltaStream packages bucket.",
+					Optional:    true,
+					Attributes: map[string]schema.Attribute{
+						"images": schema.ListAttribute{
+							Description: "Image references, relative to the DeltaStream ECR registry, to copy.",
+							ElementType: basetypes.StringType{},
+							Required:    true,
+							Validators:  []validator.List{listvalidator.SizeAtLeast(1)},
+						},
+						"exec_engine_version": schema.StringAttribute{
+							Description: "Version of the execution engine jar to install.",
+							Required:    true,
+							Validators:  []validator.String{stringvalidator.LengthAtLeast(1)},
+						},
+					},
+				},
+				"observability": schema.SingleNestedAttribute{
+					Description: "Resource limits of the dataplane metrics stack.",
+					Optional:    true,
+					Attributes: map[string]schema.Attribute{
+						"prometheus_local_tsdb_retention": schema.StringAttribute{
+							Description: "How long Prometheus keeps metrics in its local TSDB, as a Prometheus duration (default: 5d).",
+							Optional:    true,
+							Validators:  []validator.String{stringvalidator.RegexMatches(regexp.MustCompile(`^([0-9]+(y|w|d|h|m|s|ms))+$`), "Invalid Prometheus duration")},
+						},
+						"prometheus_memory_limit": schema.StringAttribute{
+							Description: "Memory limit of Prometheus (default: 4Gi).",
+							Optional:    true,
+							Validators:  []validator.String{Quantity()},
+						},
+						"prometheus_pvc_storage_size": schema.StringAttribute{
+							Description: "Size of the Prometheus persistent volume (default: 300Gi).",
+							Optional:    true,
+							Validators:  []validator.String{Quantity()},
+						},
+						"thanos_query_memory_limit": schema.StringAttribute{
+							Description: "Memory limit of Thanos query (default: 1.2Gi).",
+							Optional:    true,
+							Validators:  []validator.String{Quantity()},
+						},
+						"thanos_store_memory_limit": schema.StringAttribute{
+							Description: "Memory limit of the Thanos store gateway (default: 1.2Gi).",
+							Optional:    true,
+							Validators:  []validator.String{Quantity()},
+						},
+					},
+				},
 			},
 		},
+		"tags": schema.MapAttribute{
+			Description: "Tags applied to the AWS resources created by the provider. Tags set here take precedence over the provider default_tags.",
+			ElementType: basetypes.StringType{},
+			Optional:    true,
+		},
 		"status": schema.SingleNestedAttribute{
 			Computed: true,
 			Attributes: map[string]schema.Attribute{
@@ -523,10 +1279,26 @@ var Schema = schema.Schema{
 					Description: "The version of the DeltaStream product installed on the dataplane.",
 					Computed:    true,
 				},
+				"created_at": schema.StringAttribute{
+					Description: "The time the dataplane was created.",
+					Computed:    true,
+				},
 				"last_modified": schema.StringAttribute{
 					Description: "The time the dataplane was last updated.",
 					Computed:    true,
 				},
+				"phase": schema.StringAttribute{
+					Description: "The last phase reached while installing or updating the dataplane.",
+					Computed:    true,
+				},
+				"cluster_endpoint": schema.StringAttribute{
+					Description: "The endpoint of the EKS cluster API server.",
+					Computed:    true,
+				},
+				"oidc_issuer": schema.StringAttribute{
+					Description: "The OIDC issuer URL of the EKS cluster.",
+					Computed:    true,
+				},
 			},
 		},
 	},
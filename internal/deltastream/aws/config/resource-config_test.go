@@ -0,0 +1,60 @@
+// Copyright (c) DeltaStream, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestIdentityAttributesRequireReplace(t *testing.T) {
+	ctx := context.Background()
+	configuration := Schema.Attributes["configuration"].(schema.SingleNestedAttribute)
+	// the plan modifiers only require replacement for resources that exist and are not being destroyed
+	existing := tfsdk.State{Raw: tftypes.NewValue(tftypes.Object{}, map[string]tftypes.Value{})}
+	planned := tfsdk.Plan{Raw: tftypes.NewValue(tftypes.Object{}, map[string]tftypes.Value{})}
+
+	for _, name := range []string{"account_id", "infra_id", "eks_resource_id", "vpc_id"} {
+		t.Run(name, func(t *testing.T) {
+			attr := configuration.Attributes[name].(schema.StringAttribute)
+			resp := &planmodifier.StringResponse{PlanValue: types.StringValue("new")}
+			for _, m := range attr.PlanModifiers {
+				m.PlanModifyString(ctx, planmodifier.StringRequest{
+					Path:       path.Root("configuration").AtName(name),
+					State:      existing,
+					Plan:       planned,
+					StateValue: types.StringValue("old"),
+					PlanValue:  types.StringValue("new"),
+				}, resp)
+			}
+			if !resp.RequiresReplace {
+				t.Errorf("changing %s does not require replace", name)
+			}
+		})
+	}
+
+	t.Run("cluster_index", func(t *testing.T) {
+		attr := configuration.Attributes["cluster_index"].(schema.Int64Attribute)
+		resp := &planmodifier.Int64Response{PlanValue: types.Int64Value(1)}
+		for _, m := range attr.PlanModifiers {
+			m.PlanModifyInt64(ctx, planmodifier.Int64Request{
+				Path:       path.Root("configuration").AtName("cluster_index"),
+				State:      existing,
+				Plan:       planned,
+				StateValue: types.Int64Value(0),
+				PlanValue:  types.Int64Value(1),
+			}, resp)
+		}
+		if !resp.RequiresReplace {
+			t.Error("changing cluster_index does not require replace")
+		}
+	})
+}
@@ -0,0 +1,42 @@
+// Copyright (c) DeltaStream, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+)
+
+const (
+	StackProd = "prod"
+
+	DefaultStack = StackProd
+)
+
+// Stacks lists the known types of DeltaStream dataplane.
+var Stacks = []string{StackProd}
+
+// packagesBuckets maps each stack to the bucket holding its product packages and image lists.
+var packagesBuckets = map[string]string{
+	StackProd: "prod-ds-packages-maven",
+}
+
+// StackOrDefault returns the configured stack, or the default stack when unset.
+func StackOrDefault(stack basetypes.StringValue) string {
+	if stack.IsNull() || stack.IsUnknown() {
+		return DefaultStack
+	}
+	return stack.ValueString()
+}
+
+// PackagesBucketName returns the bucket holding the product packages of the stack.
+func PackagesBucketName(stack string) (string, error) {
+	bucket, ok := packagesBuckets[stack]
+	if !ok {
+		return "", fmt.Errorf("unknown stack %q, expected one of %s", stack, strings.Join(Stacks, ", "))
+	}
+	return bucket, nil
+}
@@ -0,0 +1,48 @@
+// Copyright (c) DeltaStream, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+)
+
+func TestPackagesBucketName(t *testing.T) {
+	tests := []struct {
+		stack   string
+		want    string
+		wantErr bool
+	}{
+		{stack: StackProd, want: "prod-ds-packages-maven"},
+		{stack: "prd", wantErr: true},
+		{stack: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.stack, func(t *testing.T) {
+			got, err := PackagesBucketName(tt.stack)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("PackagesBucketName(%q) error = %v, wantErr %v", tt.stack, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("PackagesBucketName(%q) = %q, want %q", tt.stack, got, tt.want)
+			}
+		})
+	}
+
+	for _, stack := range Stacks {
+		if _, err := PackagesBucketName(stack); err != nil {
+			t.Errorf("stack %q has no packages bucket: %v", stack, err)
+		}
+	}
+}
+
+func TestStackOrDefault(t *testing.T) {
+	if got := StackOrDefault(basetypes.NewStringNull()); got != DefaultStack {
+		t.Errorf("StackOrDefault(null) = %q, want %q", got, DefaultStack)
+	}
+	if got := StackOrDefault(basetypes.NewStringValue(StackProd)); got != StackProd {
+		t.Errorf("StackOrDefault(prod) = %q, want %q", got, StackProd)
+	}
+}
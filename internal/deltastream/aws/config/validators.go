@@ -0,0 +1,304 @@
+// Copyright (c) DeltaStream, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+var (
+	regionRegex            = regexp.MustCompile(`^[a-z]{2}(-gov|-iso[a-z]?)?-[a-z]+-[0-9]{1,2}$`)
+	securityGroupIdRegex   = regexp.MustCompile(`^sg-([0-9a-f]{8}|[0-9a-f]{17})$`)
+	securityGroupNameRegex = regexp.MustCompile(`^[a-zA-Z0-9 ._\-:/()#@\[\]+=&;{}!$*]{1,255}$`)
+)
+
+// ValidateSecurityGroup checks that the token is either a security group ID (sg-xxxxxxxx) or a security group name.
+func ValidateSecurityGroup(token string) error {
+	if strings.HasPrefix(token, "sg-") {
+		if !securityGroupIdRegex.MatchString(token) {
+			return fmt.Errorf("invalid security group ID %q", token)
+		}
+		return nil
+	}
+	if !securityGroupNameRegex.MatchString(token) {
+		return fmt.Errorf("invalid security group name %q", token)
+	}
+	return nil
+}
+
+// SplitSecurityGroups splits a comma separated list of security group IDs and/or names, surrounding whitespace is
+// trimmed from each entry.
+func SplitSecurityGroups(value string) []string {
+	if value == "" {
+		return nil
+	}
+	groups := strings.Split(value, ",")
+	for i := range groups {
+		groups[i] = strings.TrimSpace(groups[i])
+	}
+	return groups
+}
+
+var _ validator.String = securityGroupsValidator{}
+
+// securityGroupsValidator validates a comma separated list of security group IDs and/or names.
+type securityGroupsValidator struct{}
+
+func (v securityGroupsValidator) Description(_ context.Context) string {
+	return "value must be a comma separated list of security group IDs or names"
+}
+
+func (v securityGroupsValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v securityGroupsValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	for _, token := range SplitSecurityGroups(req.ConfigValue.ValueString()) {
+		if token == "" {
+			resp.Diagnostics.AddAttributeError(req.Path, "Invalid security groups", "empty security group in list")
+			continue
+		}
+		if err := ValidateSecurityGroup(token); err != nil {
+			resp.Diagnostics.AddAttributeError(req.Path, "Invalid security groups", err.Error())
+		}
+	}
+}
+
+// SecurityGroups returns a validator for a comma separated list of security group IDs and/or names.
+func SecurityGroups() validator.String {
+	return securityGroupsValidator{}
+}
+
+// knownRegions are the AWS regions known when this provider version was released
+var knownRegions = map[string]bool{
+	"af-south-1": true, "ap-east-1": true, "ap-northeast-1": true, "ap-northeast-2": true, "ap-northeast-3": true,
+	"ap-south-1": true, "ap-south-2": true, "ap-southeast-1": true, "ap-southeast-2": true, "ap-southeast-3": true,
+	"ap-southeast-4": true, "ca-central-1": true, "ca-west-1": true, "eu-central-1": true, "eu-central-2": true,
+	"eu-north-1": true, "eu-south-1": true, "eu-south-2": true, "eu-west-1": true, "eu-west-2": true, "eu-west-3": true,
+	"il-central-1": true, "me-central-1": true, "me-south-1": true, "sa-east-1": true, "us-east-1": true,
+	"us-east-2": true, "us-west-1": true, "us-west-2": true, "us-gov-east-1": true, "us-gov-west-1": true,
+}
+
+var _ validator.String = regionValidator{}
+
+// regionValidator validates the format of an AWS region and warns about regions that are not known.
+type regionValidator struct{}
+
+func (v regionValidator) Description(_ context.Context) string {
+	return "value must be an AWS region such as us-west-2"
+}
+
+func (v regionValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v regionValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	region := req.ConfigValue.ValueString()
+	if !regionRegex.MatchString(region) {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid AWS region", fmt.Sprintf("%q is not a valid AWS region", region))
+		return
+	}
+	if !knownRegions[region] {
+		resp.Diagnostics.AddAttributeWarning(req.Path, "Unknown AWS region", fmt.Sprintf("%q is not a known AWS region, check for typos if this is not a newly launched region", region))
+	}
+}
+
+// Region returns a validator for AWS region names.
+func Region() validator.String {
+	return regionValidator{}
+}
+
+// Limits on session tags accepted by sts:AssumeRole.
+const (
+	maxSessionTags           = 50
+	maxSessionTagKeyLength   = 128
+	maxSessionTagValueLength = 256
+)
+
+var (
+	sessionTagRegex     = regexp.MustCompile(`^[\p{L}\p{Z}\p{N}_.:/=+\-@]*$`)
+	sourceIdentityRegex = regexp.MustCompile(`^[\w+=,.@-]{2,64}$`)
+	sessionNameRegex    = regexp.MustCompile(`^[\w+=,.@-]{2,64}$`)
+)
+
+var _ validator.Map = sessionTagsValidator{}
+
+// sessionTagsValidator validates session tag keys and values against the limits of sts:AssumeRole.
+type sessionTagsValidator struct{}
+
+func (v sessionTagsValidator) Description(_ context.Context) string {
+	return fmt.Sprintf("value must contain at most %d tags with keys of 1-%d and values of 0-%d valid characters", maxSessionTags, maxSessionTagKeyLength, maxSessionTagValueLength)
+}
+
+func (v sessionTagsValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v sessionTagsValidator) ValidateMap(ctx context.Context, req validator.MapRequest, resp *validator.MapResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	elements := req.ConfigValue.Elements()
+	if len(elements) > maxSessionTags {
+		resp.Diagnostics.AddAttributeError(req.Path, "Too many session tags", fmt.Sprintf("at most %d session tags are allowed, got %d", maxSessionTags, len(elements)))
+	}
+	for key, value := range elements {
+		if err := ValidateSessionTag(key, value.(basetypes.StringValue).ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(req.Path.AtMapKey(key), "Invalid session tag", err.Error())
+		}
+	}
+}
+
+// ValidateSessionTag checks a session tag key and value against the length and character limits of sts:AssumeRole.
+func ValidateSessionTag(key, value string) error {
+	if n := utf8.RuneCountInString(key); n < 1 || n > maxSessionTagKeyLength {
+		return fmt.Errorf("session tag key %q must be between 1 and %d characters", key, maxSessionTagKeyLength)
+	}
+	if !sessionTagRegex.MatchString(key) {
+		return fmt.Errorf("session tag key %q contains invalid characters", key)
+	}
+	if utf8.RuneCountInString(value) > maxSessionTagValueLength {
+		return fmt.Errorf("session tag value for %q must be at most %d characters", key, maxSessionTagValueLength)
+	}
+	if !sessionTagRegex.MatchString(value) {
+		return fmt.Errorf("session tag value for %q contains invalid characters", key)
+	}
+	return nil
+}
+
+// SessionTags returns a validator for the session tags of an assumed role.
+func SessionTags() validator.Map {
+	return sessionTagsValidator{}
+}
+
+// SessionName returns a validator for the session name of an assumed role.
+func SessionName() validator.String {
+	return stringvalidator.RegexMatches(sessionNameRegex, "must be 2-64 characters of letters, digits or +=,.@_-")
+}
+
+// SourceIdentity returns a validator for the source identity of an assumed role.
+func SourceIdentity() validator.String {
+	return stringvalidator.RegexMatches(sourceIdentityRegex, "must be 2-64 characters of letters, digits or +=,.@_-")
+}
+
+var _ validator.String = quantityValidator{}
+
+// quantityValidator validates a kubernetes resource quantity such as 4Gi.
+type quantityValidator struct{}
+
+func (v quantityValidator) Description(_ context.Context) string {
+	return "value must be a kubernetes resource quantity such as 4Gi"
+}
+
+func (v quantityValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v quantityValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if _, err := resource.ParseQuantity(req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid quantity", fmt.Sprintf("%q is not a valid kubernetes quantity: %s", req.ConfigValue.ValueString(), err))
+	}
+}
+
+// Quantity returns a validator for kubernetes resource quantities.
+func Quantity() validator.String {
+	return quantityValidator{}
+}
+
+var _ validator.String = durationValidator{}
+
+// durationValidator validates a non-negative duration in the format accepted by time.ParseDuration such as 1m30s.
+type durationValidator struct{}
+
+func (v durationValidator) Description(_ context.Context) string {
+	return "value must be a non-negative duration such as 1m30s"
+}
+
+func (v durationValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v durationValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	d, err := time.ParseDuration(req.ConfigValue.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid duration", fmt.Sprintf("%q is not a valid duration: %s", req.ConfigValue.ValueString(), err))
+		return
+	}
+	if d < 0 {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid duration", fmt.Sprintf("%q must not be negative", req.ConfigValue.ValueString()))
+	}
+}
+
+// Duration returns a validator for durations such as timeouts and intervals.
+func Duration() validator.String {
+	return durationValidator{}
+}
+
+var _ validator.String = ipv6CidrValidator{}
+
+// ipv6CidrValidator validates an IPv6 CIDR such as 2600:1f14:abc:de00::/56.
+type ipv6CidrValidator struct{}
+
+func (v ipv6CidrValidator) Description(_ context.Context) string {
+	return "value must be an IPv6 CIDR such as 2600:1f14:abc:de00::/56"
+}
+
+func (v ipv6CidrValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v ipv6CidrValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if err := ValidateIpv6Cidr(req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid IPv6 CIDR", err.Error())
+	}
+}
+
+// ValidateIpv6Cidr checks that the value is an IPv6 CIDR.
+func ValidateIpv6Cidr(value string) error {
+	ip, _, err := net.ParseCIDR(value)
+	if err != nil {
+		return fmt.Errorf("%q is not a valid CIDR: %w", value, err)
+	}
+	if ip.To4() != nil {
+		return fmt.Errorf("%q is an IPv4 CIDR", value)
+	}
+	return nil
+}
+
+// Ipv6Cidr returns a validator for IPv6 CIDRs.
+func Ipv6Cidr() validator.String {
+	return ipv6CidrValidator{}
+}
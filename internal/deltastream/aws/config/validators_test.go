@@ -0,0 +1,149 @@
+// Copyright (c) DeltaStream, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestSecurityGroupsValidator(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		wantErr bool
+	}{
+		{name: "single name", value: "dp-ingress"},
+		{name: "single short id", value: "sg-0123abcd"},
+		{name: "single long id", value: "sg-0123456789abcdef0"},
+		{name: "mixed names and ids", value: "dp-ingress,sg-0123abcd,corp_vpn,sg-0123456789abcdef0"},
+		{name: "malformed id", value: "dp-ingress,sg-xyz", wantErr: true},
+		{name: "uppercase id", value: "sg-0123ABCD", wantErr: true},
+		{name: "spaces around tokens", value: "sg-0123abcd, sg-4567ef01 ,dp-ingress"},
+		{name: "space separated id not a name", value: "sg-0123abcd, sg-xyz", wantErr: true},
+		{name: "empty token", value: "dp-ingress,,sg-0123abcd", wantErr: true},
+		{name: "blank token", value: "dp-ingress, ,sg-0123abcd", wantErr: true},
+		{name: "trailing comma", value: "dp-ingress,", wantErr: true},
+		{name: "invalid name character", value: "dp|ingress", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &validator.StringResponse{}
+			SecurityGroups().ValidateString(context.Background(), validator.StringRequest{
+				Path:        path.Root("security_groups"),
+				ConfigValue: types.StringValue(tt.value),
+			}, resp)
+			if got := resp.Diagnostics.HasError(); got != tt.wantErr {
+				t.Errorf("ValidateString(%q) error = %v, want %v: %v", tt.value, got, tt.wantErr, resp.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestValidateSessionTag(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     string
+		value   string
+		wantErr bool
+	}{
+		{name: "simple", key: "team", value: "streaming"},
+		{name: "empty value", key: "team", value: ""},
+		{name: "allowed punctuation", key: "deltastream:infra-id", value: "a_b.c/d=e+f@g h"},
+		{name: "unicode letters", key: "équipe", value: "données"},
+		{name: "empty key", key: "", value: "streaming", wantErr: true},
+		{name: "key too long", key: strings.Repeat("k", 129), value: "v", wantErr: true},
+		{name: "value too long", key: "team", value: strings.Repeat("v", 257), wantErr: true},
+		{name: "invalid key character", key: "team|name", value: "v", wantErr: true},
+		{name: "invalid value character", key: "team", value: "a*b", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateSessionTag(tt.key, tt.value); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateSessionTag(%q, %q) error = %v, wantErr %v", tt.key, tt.value, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestQuantityValidator(t *testing.T) {
+	tests := []struct {
+		value   string
+		wantErr bool
+	}{
+		{value: "4Gi"},
+		{value: "1.2Gi"},
+		{value: "300G"},
+		{value: "512Mi"},
+		{value: "4GB", wantErr: true},
+		{value: "lots", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			resp := &validator.StringResponse{}
+			Quantity().ValidateString(context.Background(), validator.StringRequest{
+				Path:        path.Root("prometheus_memory_limit"),
+				ConfigValue: types.StringValue(tt.value),
+			}, resp)
+			if got := resp.Diagnostics.HasError(); got != tt.wantErr {
+				t.Errorf("ValidateString(%q) error = %v, want %v: %v", tt.value, got, tt.wantErr, resp.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestDurationValidator(t *testing.T) {
+	tests := []struct {
+		value   string
+		wantErr bool
+	}{
+		{value: "30s"},
+		{value: "1m30s"},
+		{value: "1.5h"},
+		{value: "500µs"},
+		{value: "+10m"},
+		{value: "0"},
+		{value: "-1m", wantErr: true},
+		{value: "10", wantErr: true},
+		{value: "10 minutes", wantErr: true},
+		{value: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			resp := &validator.StringResponse{}
+			Duration().ValidateString(context.Background(), validator.StringRequest{
+				Path:        path.Root("timeout"),
+				ConfigValue: types.StringValue(tt.value),
+			}, resp)
+			if got := resp.Diagnostics.HasError(); got != tt.wantErr {
+				t.Errorf("ValidateString(%q) error = %v, want %v: %v", tt.value, got, tt.wantErr, resp.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestValidateIpv6Cidr(t *testing.T) {
+	tests := []struct {
+		value   string
+		wantErr bool
+	}{
+		{value: "2600:1f14:abc:de00::/56"},
+		{value: "fd00::/8"},
+		{value: "10.0.0.0/16", wantErr: true},
+		{value: "2600:1f14:abc:de00::", wantErr: true},
+		{value: "2600:1f14::/129", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			if err := ValidateIpv6Cidr(tt.value); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateIpv6Cidr(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+		})
+	}
+}
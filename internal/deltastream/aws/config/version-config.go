@@ -0,0 +1,54 @@
+// Copyright (c) DeltaStream, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+)
+
+type AWSDataplaneVersion struct {
+	AssumeRole        basetypes.ObjectValue `tfsdk:"assume_role"`
+	Stack             basetypes.StringValue `tfsdk:"stack"`
+	ProductVersion    basetypes.StringValue `tfsdk:"product_version"`
+	Versions          basetypes.ListValue   `tfsdk:"versions"`
+	ExecEngineVersion basetypes.StringValue `tfsdk:"exec_engine_version"`
+}
+
+func (d *AWSDataplaneVersion) AssumeRoleData(ctx context.Context) (AssumeRole, diag.Diagnostics) {
+	var ar AssumeRole
+	diag := d.AssumeRole.As(ctx, &ar, basetypes.ObjectAsOptions{})
+	return ar, diag
+}
+
+var VersionDataSourceSchema = schema.Schema{
+	MarkdownDescription: "Available DeltaStream dataplane product versions",
+
+	Attributes: map[string]schema.Attribute{
+		"assume_role": assumeRoleDataSourceAttribute,
+		"stack": schema.StringAttribute{
+			Description: "The type of DeltaStream dataplane (default: prod).",
+			Optional:    true,
+			Validators:  []validator.String{stringvalidator.OneOf(Stacks...)},
+		},
+		"product_version": schema.StringAttribute{
+			Description: "The product version to look up the execution engine version for.",
+			Optional:    true,
+		},
+		"versions": schema.ListAttribute{
+			Description: "The available product versions.",
+			ElementType: basetypes.StringType{},
+			Computed:    true,
+		},
+		"exec_engine_version": schema.StringAttribute{
+			Description: "The execution engine version of the requested product version.",
+			Computed:    true,
+		},
+	},
+}
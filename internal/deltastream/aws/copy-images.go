@@ -5,155 +5,805 @@ package aws
 
 import (
 	"bytes"
+	"compress/gzip"
 	"context"
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/alitto/pond"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ecr"
+	ecrtypes "github.com/aws/aws-sdk-go-v2/service/ecr/types"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
 	"github.com/containers/image/v5/copy"
 	"github.com/containers/image/v5/docker"
+	"github.com/containers/image/v5/docker/reference"
 	"github.com/containers/image/v5/signature"
 	"github.com/containers/image/v5/types"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/sethvargo/go-retry"
+	"golang.org/x/time/rate"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"sigs.k8s.io/yaml"
 
 	awsconfig "github.com/deltastreaminc/terraform-provider-dataplane/internal/deltastream/aws/config"
+	"github.com/deltastreaminc/terraform-provider-dataplane/internal/deltastream/aws/util"
 )
 
-func copyImages(ctx context.Context, cfg aws.Config, dp awsconfig.AWSDataplane) (d diag.Diagnostics) {
+func copyImages(ctx context.Context, cfg aws.Config, dp awsconfig.AWSDataplane, defaultTags map[string]string) (d diag.Diagnostics) {
 	clusterConfig, diags := dp.ClusterConfigurationData(ctx)
 	d.Append(diags...)
 	if d.HasError() {
 		return
 	}
 
-	bucketName := "prod-ds-packages-maven"
-	if clusterConfig.Stack.ValueString() != "prod" {
-		bucketName = "deltastream-packages-maven"
+	bucketName, err := awsconfig.PackagesBucketName(clusterConfig.Stack.ValueString())
+	if err != nil {
+		d.AddAttributeError(configurationPath.AtName("stack"), "invalid stack", err.Error())
+		return
+	}
+	s3client := packagesS3Client(cfg)
+	customImageList := !(clusterConfig.ImageList.IsNull() || clusterConfig.ImageList.IsUnknown())
+	imageList, diags := resolveImageList(ctx, s3client, clusterConfig)
+	d.Append(diags...)
+	if d.HasError() {
+		return
 	}
 
-	bucketCfg := cfg.Copy()
-	bucketCfg.Region = "us-east-2"
-	s3client := s3.NewFromConfig(bucketCfg)
-	imageListPath := fmt.Sprintf("deltastream-release-images/image-list-%s.yaml", clusterConfig.ProductVersion.ValueString())
-	tflog.Debug(ctx, "downloading image list", map[string]any{
-		"bucket":          bucketName,
-		"image list path": imageListPath,
-	})
+	if clusterConfig.ImageDeliveryMode.ValueString() == awsconfig.ImageDeliveryModePullThrough {
+		d.Append(ensurePullThroughCacheRule(ctx, cfg, clusterConfig)...)
+	} else {
+		tags, diags := dataplaneTags(ctx, dp, defaultTags)
+		d.Append(diags...)
+		if d.HasError() {
+			return
+		}
+		d.Append(copyImageSet(ctx, cfg, clusterConfig, imageList.Images, tags)...)
+		if d.HasError() {
+			return
+		}
+
+		replicaRegions := []string{}
+		if !(clusterConfig.ImageReplicaRegions.IsNull() || clusterConfig.ImageReplicaRegions.IsUnknown()) {
+			d.Append(clusterConfig.ImageReplicaRegions.ElementsAs(ctx, &replicaRegions, false)...)
+		}
+		for _, region := range replicaRegions {
+			diags := replicateImageSet(ctx, cfg, clusterConfig, imageList.Images, tags, region)
+			if diags.HasError() && clusterConfig.ImageReplicaBestEffort.ValueBool() {
+				for _, e := range diags.Errors() {
+					d.AddWarning(e.Summary(), e.Detail())
+				}
+				continue
+			}
+			d.Append(diags...)
+		}
+	}
+	if d.HasError() {
+		return
+	}
+
+	execEngineUri := fmt.Sprintf("release/io/deltastream/execution-engine/%s/execution-engine-%s.jar", imageList.ExecEngineVersion, imageList.ExecEngineVersion)
+	uploadS3Client := s3.NewFromConfig(cfg)
+	if customImageList {
+		// the execution engine jar may have been pre-staged alongside a customer supplied image list
+		if _, err := uploadS3Client.HeadObject(ctx, &s3.HeadObjectInput{
+			Bucket: aws.String(clusterConfig.ProductArtifactsBucket.ValueString()),
+			Key:    aws.String(execEngineUri),
+		}); err == nil {
+			tflog.Debug(ctx, "execution engine jar already present, skipping copy", map[string]any{
+				"bucket": clusterConfig.ProductArtifactsBucket.ValueString(),
+				"uri":    execEngineUri,
+			})
+			return
+		}
+	}
+
+	// Copy the execution engine jar
+	tflog.Debug(ctx, "downloading execution engine jar "+bucketName+" "+execEngineUri)
 	getObjectOut, err := s3client.GetObject(ctx, &s3.GetObjectInput{
 		Bucket: aws.String(bucketName),
-		Key:    aws.String(imageListPath),
+		Key:    aws.String(execEngineUri),
 	})
 	if err != nil {
-		d.AddError("error getting image list", err.Error())
+		d.AddError("error downloading execution engine jar", util.AwsErrorDetail(err, util.Remediation{Action: "s3:GetObject", Attribute: "product_version"}))
 		return
 	}
 	defer getObjectOut.Body.Close()
+	b, err := io.ReadAll(getObjectOut.Body)
+	if err != nil {
+		d.AddError("error reading execution engine jar", err.Error())
+		return
+	}
 
-	imageList := struct {
-		Images            []string `json:"images"`
-		ExecEngineVersion string   `json:"execEngineVersion"`
-	}{}
+	if getObjectOut.ContentLength != nil && *getObjectOut.ContentLength != int64(len(b)) {
+		d.AddError("error downloading execution engine jar", fmt.Sprintf("downloaded %d bytes, expected %d bytes", len(b), *getObjectOut.ContentLength))
+		return
+	}
+	sum := sha256.Sum256(b)
+	checksum := base64.StdEncoding.EncodeToString(sum[:])
 
-	b, err := io.ReadAll(getObjectOut.Body)
+	tflog.Debug(ctx, "uploading execution engine jar", map[string]any{
+		"bucket":   clusterConfig.ProductArtifactsBucket.ValueString(),
+		"uri":      execEngineUri,
+		"size":     len(b),
+		"checksum": checksum,
+	})
+	// Upload the execution engine jar to the new bucket
+	_, err = uploadS3Client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:            aws.String(clusterConfig.ProductArtifactsBucket.ValueString()),
+		Key:               aws.String(execEngineUri),
+		Body:              bytes.NewReader(b),
+		ChecksumAlgorithm: s3types.ChecksumAlgorithmSha256,
+		ChecksumSHA256:    aws.String(checksum),
+	})
 	if err != nil {
-		d.AddError("error reading image list", err.Error())
+		d.AddError("error uploading execution engine jar", util.AwsErrorDetail(err, util.Remediation{Action: "s3:PutObject", Attribute: "product_artifacts_bucket"}))
 		return
 	}
-	if err := yaml.Unmarshal(b, &imageList); err != nil {
-		d.AddError("error unmarshalling image list", err.Error())
+
+	// Verify the stored object matches what was downloaded
+	headObjectOut, err := uploadS3Client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket:       aws.String(clusterConfig.ProductArtifactsBucket.ValueString()),
+		Key:          aws.String(execEngineUri),
+		ChecksumMode: s3types.ChecksumModeEnabled,
+	})
+	if err != nil {
+		d.AddError("error verifying execution engine jar", err.Error())
 		return
 	}
+	if size := aws.ToInt64(headObjectOut.ContentLength); size != int64(len(b)) {
+		d.AddError("execution engine jar verification failed", fmt.Sprintf("uploaded object is %d bytes, expected %d bytes", size, len(b)))
+		return
+	}
+	if headObjectOut.ChecksumSHA256 != nil && *headObjectOut.ChecksumSHA256 != checksum {
+		d.AddError("execution engine jar verification failed", fmt.Sprintf("uploaded object has checksum %s, expected %s", *headObjectOut.ChecksumSHA256, checksum))
+		return
+	}
+
+	return
+}
+
+const imageListPrefix = "deltastream-release-images/image-list-"
 
+// maxImageListSize caps the decoded size of an image list read from the packages bucket.
+const maxImageListSize = 4 << 20
+
+type imageList struct {
+	Images            []string `json:"images"`
+	ExecEngineVersion string   `json:"execEngineVersion"`
+}
+
+// copyImageSet copies the images from the DeltaStream registry into the dataplane account registry.
+func copyImageSet(ctx context.Context, cfg aws.Config, clusterConfig awsconfig.ClusterConfiguration, images []string, tags map[string]string) (d diag.Diagnostics) {
 	// Create an Amazon ECR service client
 	client := ecr.NewFromConfig(cfg)
 
-	authTokenOut, err := client.GetAuthorizationToken(ctx, &ecr.GetAuthorizationTokenInput{})
-	if err != nil {
-		d.AddError("error getting authorization token", err.Error())
+	imageCredContext, diags := ecrCredContext(ctx, client)
+	d.Append(diags...)
+	if d.HasError() {
 		return
 	}
 
-	tokenBytes, err := base64.StdEncoding.DecodeString(*authTokenOut.AuthorizationData[0].AuthorizationToken)
-	if err != nil {
-		d.AddError("error decoding authorization token", err.Error())
+	limiter, diags := imageCopyRateLimiter(clusterConfig)
+	d.Append(diags...)
+	if d.HasError() {
 		return
 	}
-	imageCredContext := &types.SystemContext{
-		DockerAuthConfig: &types.DockerAuthConfig{
-			Username: "AWS",
-			Password: strings.TrimPrefix(string(tokenBytes), "AWS:"),
-		},
+
+	sourceRegistry := ecrRegistry(clusterConfig.DsAccountId.ValueString(), cfg.Region)
+	policy, cleanup, diags := imageSignaturePolicy(clusterConfig, sourceRegistry, sourceRegistry, imageCredContext)
+	d.Append(diags...)
+	if d.HasError() {
+		return
 	}
+	defer cleanup()
 
-	pool := pond.New(3, 1000)
+	pool := pond.New(int(clusterConfig.ImageCopyConcurrency.ValueInt64()), 1000)
 	defer pool.StopAndWait()
 	group := pool.Group()
 
 	// dedup the image list
-	imageMap := make(map[string]bool)
-	for _, image := range imageList.Images {
-		imageMap[image] = true
+	imageMap := dedupImages(images)
+
+	markerClient := s3.NewFromConfig(cfg)
+	bucket := clusterConfig.ProductArtifactsBucket.ValueString()
+	productVersion := clusterConfig.ProductVersion.ValueString()
+	copied := readCopiedImages(ctx, markerClient, bucket, productVersion)
+	var mu sync.Mutex
+
+	if clusterConfig.CreateEcrRepositories.ValueBool() {
+		for image := range imageMap {
+			if err := createEcrRepository(ctx, client, image, clusterConfig.ProductVersion.ValueString(), tags); err != nil {
+				d.AddError("error creating ECR repository", util.AwsErrorDetail(err, util.Remediation{Action: "ecr:CreateRepository"}))
+				return
+			}
+		}
 	}
 
+	pending := 0
 	for image := range imageMap {
-		sourceImage := fmt.Sprintf("//%s.dkr.ecr.%s.amazonaws.com/%s", clusterConfig.DsAccountId.ValueString(), cfg.Region, image)
-		destImage := fmt.Sprintf("//%s.dkr.ecr.%s.amazonaws.com/%s", clusterConfig.AccountId.ValueString(), cfg.Region, image)
+		if !copied[image] {
+			pending++
+		}
+	}
+	copyStart := time.Now()
+	copiedCount := 0
+	var copiedBytes int64
+
+	for image := range imageMap {
+		if err := ctx.Err(); err != nil {
+			break
+		}
+		mu.Lock()
+		skip := copied[image]
+		mu.Unlock()
+		if skip {
+			tflog.Debug(ctx, "image copied by a previous attempt, skipping", map[string]any{"image": image})
+			continue
+		}
+		sourceImage := "//" + sourceRegistry + "/" + image
+		destImage := "//" + ecrRegistry(clusterConfig.AccountId.ValueString(), cfg.Region) + "/" + image
 
 		group.Submit(func() {
-			err = copyImage(ctx, imageCredContext, sourceImage, destImage)
+			if ctx.Err() != nil {
+				return
+			}
+			start := time.Now()
+			imageBytes, err := copyImage(ctx, imageCredContext, imageCredContext, sourceImage, destImage, policy, limiter)
+
+			mu.Lock()
+			defer mu.Unlock()
 			if err != nil {
 				d.AddError("error copying image", err.Error())
 				return
 			}
+			copied[image] = true
+			copiedCount++
+			copiedBytes += imageBytes
+			tflog.Info(ctx, fmt.Sprintf("copied %d/%d", copiedCount, pending), map[string]any{
+				"image":    image,
+				"duration": time.Since(start).Round(time.Second).String(),
+				"bytes":    imageBytes,
+			})
+			writeCopiedImages(ctx, markerClient, bucket, productVersion, copied)
 		})
 	}
 
 	group.Wait()
+	tflog.Info(ctx, "image copy finished", map[string]any{
+		"copied":   copiedCount,
+		"pending":  pending,
+		"skipped":  len(imageMap) - pending,
+		"bytes":    copiedBytes,
+		"duration": time.Since(copyStart).Round(time.Second).String(),
+	})
+	if err := ctx.Err(); err != nil {
+		d.AddError("image copy cancelled", err.Error())
+		return
+	}
+	if d.HasError() {
+		return
+	}
 
-	execEngineUri := fmt.Sprintf("release/io/deltastream/execution-engine/%s/execution-engine-%s.jar", imageList.ExecEngineVersion, imageList.ExecEngineVersion)
-	// Copy the execution engine jar
-	tflog.Debug(ctx, "downloading execution engine jar "+bucketName+" "+execEngineUri)
-	getObjectOut, err = s3client.GetObject(ctx, &s3.GetObjectInput{
+	// every image is in place, the next copy starts from scratch
+	if _, err := markerClient.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(copiedImagesMarkerKey),
+	}); err != nil {
+		tflog.Warn(ctx, "unable to remove copied images marker", map[string]any{"error": err.Error()})
+	}
+	return
+}
+
+// replicateImageSet copies the images from the dataplane ECR registry in the primary region to the dataplane ECR
+// registry in the replica region.
+func replicateImageSet(ctx context.Context, cfg aws.Config, clusterConfig awsconfig.ClusterConfiguration, images []string, tags map[string]string, region string) (d diag.Diagnostics) {
+	sourceCredContext, diags := ecrCredContext(ctx, ecr.NewFromConfig(cfg))
+	d.Append(diags...)
+	if d.HasError() {
+		return
+	}
+
+	replicaCfg := cfg.Copy()
+	replicaCfg.Region = region
+	client := ecr.NewFromConfig(replicaCfg)
+	destCredContext, diags := ecrCredContext(ctx, client)
+	d.Append(diags...)
+	if d.HasError() {
+		return
+	}
+
+	limiter, diags := imageCopyRateLimiter(clusterConfig)
+	d.Append(diags...)
+	if d.HasError() {
+		return
+	}
+
+	// the images were verified when they were copied from the DeltaStream registry, their signatures were made for it
+	policy, cleanup, diags := imageSignaturePolicy(clusterConfig,
+		ecrRegistry(clusterConfig.AccountId.ValueString(), cfg.Region),
+		ecrRegistry(clusterConfig.DsAccountId.ValueString(), cfg.Region),
+		sourceCredContext, destCredContext)
+	d.Append(diags...)
+	if d.HasError() {
+		return
+	}
+	defer cleanup()
+
+	imageMap := dedupImages(images)
+	if clusterConfig.CreateEcrRepositories.ValueBool() {
+		for image := range imageMap {
+			if err := createEcrRepository(ctx, client, image, clusterConfig.ProductVersion.ValueString(), tags); err != nil {
+				d.AddError("error creating ECR repository in "+region, util.AwsErrorDetail(err, util.Remediation{Action: "ecr:CreateRepository"}))
+				return
+			}
+		}
+	}
+
+	pool := pond.New(int(clusterConfig.ImageCopyConcurrency.ValueInt64()), 1000)
+	defer pool.StopAndWait()
+	group := pool.Group()
+
+	var mu sync.Mutex
+	replicated := 0
+	for image := range imageMap {
+		if err := ctx.Err(); err != nil {
+			break
+		}
+		sourceImage := "//" + ecrRegistry(clusterConfig.AccountId.ValueString(), cfg.Region) + "/" + image
+		destImage := "//" + ecrRegistry(clusterConfig.AccountId.ValueString(), region) + "/" + image
+
+		group.Submit(func() {
+			if ctx.Err() != nil {
+				return
+			}
+			start := time.Now()
+			_, err := copyImage(ctx, sourceCredContext, destCredContext, sourceImage, destImage, policy, limiter)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				d.AddError("error replicating image to "+region, err.Error())
+				return
+			}
+			replicated++
+			tflog.Info(ctx, fmt.Sprintf("replicated %d/%d to %s", replicated, len(imageMap), region), map[string]any{
+				"image":    image,
+				"duration": time.Since(start).Round(time.Second).String(),
+			})
+		})
+	}
+
+	group.Wait()
+	if err := ctx.Err(); err != nil {
+		d.AddError("image replication cancelled", err.Error())
+	}
+	return
+}
+
+// ecrRegistry returns the host of the ECR registry of the account in the region.
+func ecrRegistry(accountId, region string) string {
+	return fmt.Sprintf("%s.dkr.ecr.%s.amazonaws.com", accountId, region)
+}
+
+// ecrCredContext returns the image copy credentials for the ECR registries of the client's region.
+func ecrCredContext(ctx context.Context, client *ecr.Client) (_ *types.SystemContext, d diag.Diagnostics) {
+	authTokenOut, err := client.GetAuthorizationToken(ctx, &ecr.GetAuthorizationTokenInput{})
+	if err != nil {
+		d.AddError("error getting authorization token", util.AwsErrorDetail(err, util.Remediation{Action: "ecr:GetAuthorizationToken"}))
+		return
+	}
+
+	tokenBytes, err := base64.StdEncoding.DecodeString(*authTokenOut.AuthorizationData[0].AuthorizationToken)
+	if err != nil {
+		d.AddError("error decoding authorization token", err.Error())
+		return
+	}
+	return &types.SystemContext{
+		DockerAuthConfig: &types.DockerAuthConfig{
+			Username: "AWS",
+			Password: strings.TrimPrefix(string(tokenBytes), "AWS:"),
+		},
+	}, d
+}
+
+// imageCopyRateLimiter returns the limiter for the configured image copy rate limit, nil when unlimited.
+func imageCopyRateLimiter(clusterConfig awsconfig.ClusterConfiguration) (_ *rate.Limiter, d diag.Diagnostics) {
+	if clusterConfig.ImageCopyRateLimit.IsNull() || clusterConfig.ImageCopyRateLimit.IsUnknown() {
+		return nil, d
+	}
+	rateLimit, err := resource.ParseQuantity(clusterConfig.ImageCopyRateLimit.ValueString())
+	if err != nil {
+		d.AddError("invalid image copy rate limit", err.Error())
+		return
+	}
+	return newCopyRateLimiter(rateLimit.Value()), d
+}
+
+// copiedImagesMarkerKey is the key of the object in the product artifacts bucket recording the images copied by an
+// image copy that has not completed yet, so that a retried apply resumes where the previous one stopped.
+const copiedImagesMarkerKey = "deltastream-copied-images.json"
+
+type copiedImagesMarker struct {
+	ProductVersion string   `json:"productVersion"`
+	Images         []string `json:"images"`
+}
+
+// readCopiedImages returns the images recorded as copied for the product version. A missing or unreadable marker, or
+// a marker recorded for a different product version, yields an empty set.
+func readCopiedImages(ctx context.Context, client *s3.Client, bucket, productVersion string) map[string]bool {
+	copied := map[string]bool{}
+	getObjectOut, err := client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(copiedImagesMarkerKey),
+	})
+	if err != nil {
+		var noSuchKey *s3types.NoSuchKey
+		if !errors.As(err, &noSuchKey) {
+			tflog.Warn(ctx, "unable to read copied images marker, copying all images", map[string]any{"error": err.Error()})
+		}
+		return copied
+	}
+	defer getObjectOut.Body.Close()
+
+	marker := copiedImagesMarker{}
+	if err := json.NewDecoder(getObjectOut.Body).Decode(&marker); err != nil {
+		tflog.Warn(ctx, "unable to decode copied images marker, copying all images", map[string]any{"error": err.Error()})
+		return copied
+	}
+	if marker.ProductVersion != productVersion {
+		tflog.Debug(ctx, "ignoring copied images marker for another product version", map[string]any{"product version": marker.ProductVersion})
+		return copied
+	}
+	for _, image := range marker.Images {
+		copied[image] = true
+	}
+	return copied
+}
+
+// writeCopiedImages records the copied images. Failing to record them only costs a re-copy on retry.
+func writeCopiedImages(ctx context.Context, client *s3.Client, bucket, productVersion string, copied map[string]bool) {
+	marker := copiedImagesMarker{ProductVersion: productVersion, Images: make([]string, 0, len(copied))}
+	for image := range copied {
+		marker.Images = append(marker.Images, image)
+	}
+	sort.Strings(marker.Images)
+
+	b, err := json.Marshal(marker)
+	if err != nil {
+		return
+	}
+	if _, err := client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(bucket),
+		Key:         aws.String(copiedImagesMarkerKey),
+		Body:        bytes.NewReader(b),
+		ContentType: aws.String("application/json"),
+	}); err != nil {
+		tflog.Warn(ctx, "unable to record copied images", map[string]any{"error": err.Error()})
+	}
+}
+
+func packagesS3Client(cfg aws.Config) *s3.Client {
+	bucketCfg := cfg.Copy()
+	bucketCfg.Region = "us-east-2"
+	return s3.NewFromConfig(bucketCfg)
+}
+
+func getImageList(ctx context.Context, s3client *s3.Client, bucketName string, productVersion string) (imgList imageList, d diag.Diagnostics) {
+	imageListPath := fmt.Sprintf("%s%s.yaml", imageListPrefix, productVersion)
+	tflog.Debug(ctx, "downloading image list", map[string]any{
+		"bucket":          bucketName,
+		"image list path": imageListPath,
+	})
+	getObjectOut, err := s3client.GetObject(ctx, &s3.GetObjectInput{
 		Bucket: aws.String(bucketName),
-		Key:    aws.String(execEngineUri),
+		Key:    aws.String(imageListPath),
 	})
 	if err != nil {
-		d.AddError("error downloading execution engine jar", err.Error())
+		d.AddError("error getting image list", util.AwsErrorDetail(err, util.Remediation{Action: "s3:GetObject", Attribute: "product_version"}))
 		return
 	}
 	defer getObjectOut.Body.Close()
-	b, err = io.ReadAll(getObjectOut.Body)
+
+	b, err := readObjectBody(getObjectOut.Body, aws.ToString(getObjectOut.ContentEncoding), maxImageListSize)
 	if err != nil {
-		d.AddError("error reading execution engine jar", err.Error())
+		d.AddError("error reading image list "+imageListPath, err.Error())
 		return
 	}
+	if err := yaml.Unmarshal(b, &imgList); err != nil {
+		d.AddError("error unmarshalling image list", err.Error())
+		return
+	}
+	return
+}
 
-	tflog.Debug(ctx, "uploading execution engine jar", map[string]any{
-		"bucket": clusterConfig.ProductArtifactsBucket.ValueString(),
-		"uri":    execEngineUri,
-		"size":   len(b),
+// readObjectBody reads an S3 object body, decompressing it when its content encoding is gzip. Bodies larger than limit
+// bytes once decompressed are rejected.
+func readObjectBody(body io.Reader, contentEncoding string, limit int64) ([]byte, error) {
+	for _, encoding := range strings.Split(contentEncoding, ",") {
+		switch strings.TrimSpace(strings.ToLower(encoding)) {
+		case "", "identity", "aws-chunked":
+		case "gzip", "x-gzip":
+			gz, err := gzip.NewReader(body)
+			if err != nil {
+				return nil, fmt.Errorf("invalid gzip content: %w", err)
+			}
+			defer gz.Close()
+			body = gz
+		default:
+			return nil, fmt.Errorf("unsupported content encoding %q", encoding)
+		}
+	}
+
+	b, err := io.ReadAll(io.LimitReader(body, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(b)) > limit {
+		return nil, fmt.Errorf("content exceeds the maximum size of %d bytes", limit)
+	}
+	return b, nil
+}
+
+// imageRepositoryName strips the tag and digest from an image reference
+func imageRepositoryName(image string) string {
+	if i := strings.Index(image, "@"); i >= 0 {
+		image = image[:i]
+	}
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		image = image[:i]
+	}
+	return image
+}
+
+// productVersionTagKey tags destination repositories with the product version whose images were last copied.
+const productVersionTagKey = "deltastream:product-version"
+
+// createEcrRepository ensures the destination repository exists with scan on push enabled and is tagged with the
+// dataplane tags and the product version being copied.
+func createEcrRepository(ctx context.Context, client *ecr.Client, image string, productVersion string, dataplaneTags map[string]string) error {
+	repositoryName := imageRepositoryName(image)
+	tags := []ecrtypes.Tag{}
+	for _, k := range util.SortedTagKeys(dataplaneTags) {
+		if k != productVersionTagKey {
+			tags = append(tags, ecrtypes.Tag{Key: aws.String(k), Value: aws.String(dataplaneTags[k])})
+		}
+	}
+	tags = append(tags, ecrtypes.Tag{Key: aws.String(productVersionTagKey), Value: aws.String(productVersion)})
+	tflog.Debug(ctx, "creating ECR repository", map[string]any{"repository": repositoryName})
+	_, err := client.CreateRepository(ctx, &ecr.CreateRepositoryInput{
+		RepositoryName:             aws.String(repositoryName),
+		ImageScanningConfiguration: &ecrtypes.ImageScanningConfiguration{ScanOnPush: true},
+		Tags:                       tags,
 	})
-	uploadS3Client := s3.NewFromConfig(cfg)
-	// Upload the execution engine jar to the new bucket
-	_, err = uploadS3Client.PutObject(ctx, &s3.PutObjectInput{
-		Bucket: aws.String(clusterConfig.ProductArtifactsBucket.ValueString()),
-		Key:    aws.String(execEngineUri),
-		Body:   bytes.NewBuffer(b),
+	if err == nil {
+		return nil
+	}
+	var alreadyExists *ecrtypes.RepositoryAlreadyExistsException
+	if !errors.As(err, &alreadyExists) {
+		return fmt.Errorf("unable to create repository %s: %w", repositoryName, err)
+	}
+
+	// bring pre-existing repositories in line with repositories created by the provider
+	if _, err := client.PutImageScanningConfiguration(ctx, &ecr.PutImageScanningConfigurationInput{
+		RepositoryName:             aws.String(repositoryName),
+		ImageScanningConfiguration: &ecrtypes.ImageScanningConfiguration{ScanOnPush: true},
+	}); err != nil {
+		return fmt.Errorf("unable to enable scan on push for repository %s: %w", repositoryName, err)
+	}
+	describeOut, err := client.DescribeRepositories(ctx, &ecr.DescribeRepositoriesInput{
+		RepositoryNames: []string{repositoryName},
+	})
+	if err != nil {
+		return fmt.Errorf("unable to describe repository %s: %w", repositoryName, err)
+	}
+	for _, repo := range describeOut.Repositories {
+		if _, err := client.TagResource(ctx, &ecr.TagResourceInput{
+			ResourceArn: repo.RepositoryArn,
+			Tags:        tags,
+		}); err != nil {
+			return fmt.Errorf("unable to tag repository %s: %w", repositoryName, err)
+		}
+	}
+	return nil
+}
+
+// pullThroughCachePrefix maps every repository in the dataplane registry onto the same path in the upstream registry so
+// that images are referenced by the same names as when they are copied.
+const pullThroughCachePrefix = "ROOT"
+
+// ensurePullThroughCacheRule configures an ECR pull-through cache rule in the dataplane account that mirrors images
+// from the DeltaStream registry on first pull. An existing rule pointing at a different upstream is replaced.
+func ensurePullThroughCacheRule(ctx context.Context, cfg aws.Config, clusterConfig awsconfig.ClusterConfiguration) (d diag.Diagnostics) {
+	client := ecr.NewFromConfig(cfg)
+	upstreamRegistryUrl := ecrRegistry(clusterConfig.DsAccountId.ValueString(), cfg.Region)
+
+	rules, err := client.DescribePullThroughCacheRules(ctx, &ecr.DescribePullThroughCacheRulesInput{
+		EcrRepositoryPrefixes: []string{pullThroughCachePrefix},
 	})
 	if err != nil {
-		d.AddError("error uploading execution engine jar", err.Error())
+		var notFound *ecrtypes.PullThroughCacheRuleNotFoundException
+		if !errors.As(err, &notFound) {
+			d.AddError("error describing ECR pull-through cache rules", util.AwsErrorDetail(err, util.Remediation{Action: "ecr:DescribePullThroughCacheRules"}))
+			return
+		}
+	} else {
+		for _, rule := range rules.PullThroughCacheRules {
+			if aws.ToString(rule.UpstreamRegistryUrl) == upstreamRegistryUrl {
+				tflog.Debug(ctx, "ECR pull-through cache rule already configured", map[string]any{"upstream": upstreamRegistryUrl})
+				return
+			}
+			tflog.Debug(ctx, "replacing ECR pull-through cache rule", map[string]any{"upstream": aws.ToString(rule.UpstreamRegistryUrl)})
+			if _, err := client.DeletePullThroughCacheRule(ctx, &ecr.DeletePullThroughCacheRuleInput{
+				EcrRepositoryPrefix: rule.EcrRepositoryPrefix,
+			}); err != nil {
+				d.AddError("error deleting ECR pull-through cache rule", util.AwsErrorDetail(err, util.Remediation{Action: "ecr:DeletePullThroughCacheRule"}))
+				return
+			}
+		}
+	}
+
+	tflog.Debug(ctx, "creating ECR pull-through cache rule", map[string]any{"upstream": upstreamRegistryUrl})
+	_, err = client.CreatePullThroughCacheRule(ctx, &ecr.CreatePullThroughCacheRuleInput{
+		EcrRepositoryPrefix: aws.String(pullThroughCachePrefix),
+		UpstreamRegistryUrl: aws.String(upstreamRegistryUrl),
+		UpstreamRegistry:    ecrtypes.UpstreamRegistry("ecr"),
+	})
+	if err != nil {
+		var alreadyExists *ecrtypes.PullThroughCacheRuleAlreadyExistsException
+		if errors.As(err, &alreadyExists) {
+			return
+		}
+		d.AddError("error creating ECR pull-through cache rule", util.AwsErrorDetail(err, util.Remediation{Action: "ecr:CreatePullThroughCacheRule"}))
+		return
+	}
+	return
+}
+
+// checkProductVersion verifies that the image list of the product version is published, so that an invalid version
+// fails before anything is changed. Customer supplied image lists are not checked.
+func checkProductVersion(ctx context.Context, cfg aws.Config, clusterConfig awsconfig.ClusterConfiguration) (d diag.Diagnostics) {
+	if !(clusterConfig.ImageList.IsNull() || clusterConfig.ImageList.IsUnknown()) {
+		return
+	}
+
+	bucketName, err := awsconfig.PackagesBucketName(clusterConfig.Stack.ValueString())
+	if err != nil {
+		d.AddAttributeError(configurationPath.AtName("stack"), "invalid stack", err.Error())
+		return
+	}
+	productVersion := clusterConfig.ProductVersion.ValueString()
+	if _, err := packagesS3Client(cfg).HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(bucketName),
+		Key:    aws.String(fmt.Sprintf("%s%s.yaml", imageListPrefix, productVersion)),
+	}); err != nil {
+		var notFound *s3types.NotFound
+		if errors.As(err, &notFound) {
+			d.AddAttributeError(configurationPath.AtName("product_version"), "product version not found", fmt.Sprintf("product version %s not found in bucket %s", productVersion, bucketName))
+			return
+		}
+		d.AddError("unable to verify product version "+productVersion, util.AwsErrorDetail(err, util.Remediation{Action: "s3:GetObject"}))
+	}
+	return
+}
+
+// resolveImageList returns the customer supplied image list when configured, otherwise the image list is fetched from
+// the packages bucket.
+func resolveImageList(ctx context.Context, s3client *s3.Client, clusterConfig awsconfig.ClusterConfiguration) (imgList imageList, d diag.Diagnostics) {
+	if !(clusterConfig.ImageList.IsNull() || clusterConfig.ImageList.IsUnknown()) {
+		imgList, d = customerImageList(ctx, clusterConfig)
+	} else {
+		bucketName, err := awsconfig.PackagesBucketName(clusterConfig.Stack.ValueString())
+		if err != nil {
+			d.AddAttributeError(configurationPath.AtName("stack"), "invalid stack", err.Error())
+			return
+		}
+		imgList, d = getImageList(ctx, s3client, bucketName, clusterConfig.ProductVersion.ValueString())
+	}
+	if d.HasError() {
+		return
+	}
+	d.Append(validateImageList(imgList.Images)...)
+	return
+}
+
+// imageListRegistry stands in for the registry host when validating image list entries, entries are repository paths
+// within the registry and the host is only added when the images are copied.
+const imageListRegistry = "registry.invalid"
+
+// validateImageList checks that every image list entry is a repository path with a tag or digest, so that a malformed
+// list fails before any image is copied. All invalid entries are reported together.
+func validateImageList(images []string) (d diag.Diagnostics) {
+	invalid := []string{}
+	for i, image := range images {
+		if err := validateImageListEntry(image); err != nil {
+			invalid = append(invalid, fmt.Sprintf("images[%d] %q: %s", i, image, err))
+		}
+	}
+	if len(invalid) > 0 {
+		d.AddError(fmt.Sprintf("invalid image list: %d of %d entries are not valid image references", len(invalid), len(images)), strings.Join(invalid, "\n"))
+	}
+	return
+}
+
+func validateImageListEntry(image string) error {
+	if strings.TrimSpace(image) == "" {
+		return errors.New("empty image reference")
+	}
+	named, err := reference.ParseNamed(imageListRegistry + "/" + image)
+	if err != nil {
+		return err
+	}
+	if _, ok := named.(reference.Tagged); ok {
+		return nil
+	}
+	if _, ok := named.(reference.Digested); ok {
+		return nil
+	}
+	return errors.New("missing tag or digest")
+}
+
+// plannedImageCopies returns the number of images that will be copied when moving from the old to the new
+// configuration. All images are copied when the destination account changes.
+func plannedImageCopies(ctx context.Context, cfg aws.Config, oldConfig, newConfig awsconfig.ClusterConfiguration) (int, diag.Diagnostics) {
+	s3client := packagesS3Client(cfg)
+	newImages, d := resolveImageList(ctx, s3client, newConfig)
+	if d.HasError() {
+		return 0, d
+	}
+
+	if !oldConfig.AccountId.Equal(newConfig.AccountId) {
+		return len(dedupImages(newImages.Images)), d
+	}
+
+	oldImages, diags := resolveImageList(ctx, s3client, oldConfig)
+	d.Append(diags...)
+	if d.HasError() {
+		return 0, d
+	}
+
+	existing := dedupImages(oldImages.Images)
+	count := 0
+	for image := range dedupImages(newImages.Images) {
+		if !existing[image] {
+			count++
+		}
+	}
+	return count, d
+}
+
+func dedupImages(images []string) map[string]bool {
+	imageMap := make(map[string]bool, len(images))
+	for _, image := range images {
+		imageMap[image] = true
+	}
+	return imageMap
+}
+
+// customerImageList builds the image list from the image_list cluster configuration
+func customerImageList(ctx context.Context, clusterConfig awsconfig.ClusterConfiguration) (imgList imageList, d diag.Diagnostics) {
+	il, diags := clusterConfig.ImageListData(ctx)
+	d.Append(diags...)
+	if d.HasError() {
 		return
 	}
 
+	d.Append(il.Images.ElementsAs(ctx, &imgList.Images, false)...)
+	if d.HasError() {
+		return
+	}
+	imgList.ExecEngineVersion = il.ExecEngineVersion.ValueString()
+	tflog.Debug(ctx, "using customer supplied image list", map[string]any{"images": imgList.Images, "exec engine version": imgList.ExecEngineVersion})
 	return
 }
 
@@ -162,7 +812,10 @@ type imgBlob struct {
 	totalBytes  float64
 }
 
-func copyImage(ctx context.Context, credContext *types.SystemContext, sourceImage, destImage string) (err error) {
+// copyImage copies the image accepted by the signature policy and returns the size of the blobs reported as
+// transferred. Blobs already present in the destination are not counted. A non-nil limiter throttles the blob reads
+// from the source registry.
+func copyImage(ctx context.Context, sourceCredContext, destCredContext *types.SystemContext, sourceImage, destImage string, policy *signature.Policy, limiter *rate.Limiter) (copiedBytes int64, err error) {
 	tflog.Debug(ctx, "copying image", map[string]any{
 		"source": sourceImage,
 		"dest":   destImage,
@@ -170,27 +823,30 @@ func copyImage(ctx context.Context, credContext *types.SystemContext, sourceImag
 
 	srcRef, err := docker.ParseReference(sourceImage)
 	if err != nil {
-		return fmt.Errorf("error parsing source image: %w", err)
+		return 0, fmt.Errorf("error parsing source image: %w", err)
+	}
+	if limiter != nil {
+		srcRef = rateLimitedReference{ImageReference: srcRef, limiter: limiter}
 	}
 
 	destRef, err := docker.ParseReference(destImage)
 	if err != nil {
-		return fmt.Errorf("error parsing destination image: %w", err)
+		return 0, fmt.Errorf("error parsing destination image: %w", err)
 	}
 
-	policy := &signature.Policy{Default: []signature.PolicyRequirement{signature.NewPRInsecureAcceptAnything()}}
 	policyContext, err := signature.NewPolicyContext(policy)
 	if err != nil {
-		return fmt.Errorf("error creating new policy context: %w", err)
+		return 0, fmt.Errorf("error creating new policy context: %w", err)
 	}
 
 	b := bytes.NewBuffer(nil)
 	reportCtx, cancel := context.WithCancel(ctx)
 	defer cancel()
 	progressChan := make(chan types.ProgressProperties)
+	blobs := map[string]imgBlob{}
+	var blobsMu sync.Mutex
 
 	go func() {
-		blobs := map[string]imgBlob{}
 		for {
 			select {
 			case <-reportCtx.Done():
@@ -201,6 +857,7 @@ func copyImage(ctx context.Context, credContext *types.SystemContext, sourceImag
 					tflog.Info(ctx, fmt.Sprintf("%s: 100.0\n", destImage))
 				}
 
+				blobsMu.Lock()
 				blobs[p.Artifact.Digest.String()] = imgBlob{
 					copiedBytes: float64(p.Offset),
 					totalBytes:  float64(p.Artifact.Size),
@@ -212,6 +869,7 @@ func copyImage(ctx context.Context, credContext *types.SystemContext, sourceImag
 					copiedBytes += a.copiedBytes
 					totalBytes += a.totalBytes
 				}
+				blobsMu.Unlock()
 				if totalBytes == 0 {
 					continue
 				}
@@ -223,8 +881,8 @@ func copyImage(ctx context.Context, credContext *types.SystemContext, sourceImag
 
 	err = retry.Do(ctx, retry.WithMaxRetries(20, retry.NewExponential(time.Second*5)), func(ctx context.Context) error {
 		_, err := copy.Image(ctx, policyContext, destRef, srcRef, &copy.Options{
-			SourceCtx:          credContext,
-			DestinationCtx:     credContext,
+			SourceCtx:          sourceCredContext,
+			DestinationCtx:     destCredContext,
 			ReportWriter:       b,
 			Progress:           progressChan,
 			ProgressInterval:   time.Second * 10,
@@ -237,8 +895,13 @@ func copyImage(ctx context.Context, credContext *types.SystemContext, sourceImag
 		return err
 	})
 	if err != nil {
-		return fmt.Errorf("error copying image: %w\n%s", err, b.String())
+		return 0, fmt.Errorf("error copying image: %w\n%s", err, b.String())
 	}
 
-	return
+	blobsMu.Lock()
+	defer blobsMu.Unlock()
+	for _, a := range blobs {
+		copiedBytes += int64(a.totalBytes)
+	}
+	return copiedBytes, nil
 }
@@ -0,0 +1,109 @@
+// Copyright (c) DeltaStream, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package aws
+
+import (
+	"bytes"
+	"compress/gzip"
+	"strings"
+	"testing"
+)
+
+func TestValidateImageList(t *testing.T) {
+	const digest = "sha256:2cf2ab62a7a0ac3a6fd6e8e0cd0b0ba8f4bfd8c8b8e0e0f4f9a4f0f4e2b6a0c1"
+
+	tests := []struct {
+		name        string
+		images      []string
+		wantInvalid []string
+	}{
+		{name: "valid", images: []string{"deltastream/api-server:1.2.3", "deltastream/dp-operator@" + digest, "cilium/cilium:v1.15.5@" + digest}},
+		{name: "missing tag", images: []string{"deltastream/api-server:1.2.3", "deltastream/dp-operator"}, wantInvalid: []string{"images[1]"}},
+		{name: "empty and malformed", images: []string{"", "deltastream/api-server:", "Deltastream/API:1.0"}, wantInvalid: []string{"images[0]", "images[1]", "images[2]"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := validateImageList(tt.images)
+			if len(tt.wantInvalid) == 0 {
+				if d.HasError() {
+					t.Fatalf("validateImageList() = %v, want no error", d)
+				}
+				return
+			}
+			if d.ErrorsCount() != 1 {
+				t.Fatalf("validateImageList() returned %d errors, want 1", d.ErrorsCount())
+			}
+			detail := d.Errors()[0].Detail()
+			for _, want := range tt.wantInvalid {
+				if !strings.Contains(detail, want) {
+					t.Errorf("validateImageList() detail %q does not report %s", detail, want)
+				}
+			}
+		})
+	}
+}
+
+func TestReadObjectBody(t *testing.T) {
+	const content = "images:\n  - deltastream/api-server:1.2.3\n"
+	gzipped := bytes.Buffer{}
+	gz := gzip.NewWriter(&gzipped)
+	gz.Write([]byte(strings.Repeat(content, 100)))
+	gz.Close()
+
+	tests := []struct {
+		name            string
+		body            []byte
+		contentEncoding string
+		limit           int64
+		want            string
+		wantErr         string
+	}{
+		{name: "plain", body: []byte(content), limit: 1024, want: content},
+		{name: "gzip", body: gzipped.Bytes(), contentEncoding: "gzip", limit: 1 << 20, want: strings.Repeat(content, 100)},
+		{name: "gzip exceeds limit once decompressed", body: gzipped.Bytes(), contentEncoding: "gzip", limit: 1024, wantErr: "maximum size"},
+		{name: "plain exceeds limit", body: []byte(content), limit: 10, wantErr: "maximum size"},
+		{name: "invalid gzip", body: []byte(content), contentEncoding: "gzip", limit: 1024, wantErr: "invalid gzip"},
+		{name: "unsupported encoding", body: []byte(content), contentEncoding: "br", limit: 1024, wantErr: "unsupported content encoding"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := readObjectBody(bytes.NewReader(tt.body), tt.contentEncoding, tt.limit)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("readObjectBody() error = %v, want error containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("readObjectBody() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("readObjectBody() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestImageRepositoryName(t *testing.T) {
+	const digest = "sha256:2cf2ab62a7a0ac3a6fd6e8e0cd0b0ba8f4bfd8c8b8e0e0f4f9a4f0f4e2b6a0c1"
+
+	tests := []struct {
+		name  string
+		image string
+		want  string
+	}{
+		{name: "tag", image: "deltastream/api-server:1.2.3", want: "deltastream/api-server"},
+		{name: "digest", image: "deltastream/dp-operator@" + digest, want: "deltastream/dp-operator"},
+		{name: "tag and digest", image: "cilium/cilium:v1.15.5@" + digest, want: "cilium/cilium"},
+		{name: "registry with port", image: "registry.example.com:5000/deltastream/api-server:1.2.3", want: "registry.example.com:5000/deltastream/api-server"},
+		{name: "registry with port without tag", image: "registry.example.com:5000/deltastream/api-server", want: "registry.example.com:5000/deltastream/api-server"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := imageRepositoryName(tt.image); got != tt.want {
+				t.Errorf("imageRepositoryName(%q) = %q, want %q", tt.image, got, tt.want)
+			}
+		})
+	}
+}
@@ -6,14 +6,20 @@ package aws
 import (
 	"context"
 	_ "embed"
+	"fmt"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/containers/image/v5/docker/reference"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/sethvargo/go-retry"
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	awsconfig "github.com/deltastreaminc/terraform-provider-dataplane/internal/deltastream/aws/config"
@@ -23,16 +29,16 @@ import (
 //go:embed assets/custom-credentials.yaml.tmpl
 var customCredentialKustomization []byte
 
-func deployCustomCredentialsContiner(ctx context.Context, cfg aws.Config, dp awsconfig.AWSDataplane) (d diag.Diagnostics) {
+func deployCustomCredentialsContiner(ctx context.Context, cfg aws.Config, dp awsconfig.AWSDataplane, getKubeClient kubeClientFactory) (d diag.Diagnostics) {
 	clusterConfig, diags := dp.ClusterConfigurationData(ctx)
 	d.Append(diags...)
 	if d.HasError() {
 		return
 	}
 
-	kubeClient, err := util.GetKubeClient(ctx, cfg, dp)
-	if err != nil {
-		d.AddError("error getting kube client", err.Error())
+	kubeClient, diags := getKubeClient(ctx, cfg, dp)
+	d.Append(diags...)
+	if d.HasError() {
 		return
 	}
 
@@ -40,31 +46,33 @@ func deployCustomCredentialsContiner(ctx context.Context, cfg aws.Config, dp aws
 		return
 	}
 
-	imgSpl := strings.Split(clusterConfig.CustomCredentialsImage.ValueString(), ":")
-	if len(imgSpl) != 2 {
-		d.AddError("invalid custom credentials image", clusterConfig.CustomCredentialsImage.ValueString())
+	imageRepository, imageTag, imageDigest, err := parseImageReference(clusterConfig.CustomCredentialsImage.ValueString())
+	if err != nil {
+		d.AddError("invalid custom credentials image "+clusterConfig.CustomCredentialsImage.ValueString(), err.Error())
 		return
 	}
 
 	d.Append(util.RenderAndApplyTemplate(ctx, kubeClient, "custom credentials", customCredentialKustomization, map[string]string{
-		"Region":          cfg.Region,
-		"AccountID":       clusterConfig.AccountId.ValueString(),
-		"ImageRepository": imgSpl[0],
-		"ImageTag":        imgSpl[1],
-		"ProductVersion":  clusterConfig.ProductVersion.ValueString(),
-	})...)
-	if d.HasError() {
+		"Region":                 cfg.Region,
+		"AccountID":              clusterConfig.AccountId.ValueString(),
+		"ImageRepository":        imageRepository,
+		"ImageTag":               imageTag,
+		"ImageDigest":            imageDigest,
+		"ProductVersion":         clusterConfig.ProductVersion.ValueString(),
+		"ClusterConfigNamespace": clusterConfig.Namespaces().ClusterConfig,
+	}, util.TemplateOptions{})...)
+	if d.HasError() || kubeClient.DryRun() {
 		return
 	}
 
-	if err = retry.Do(ctx, retry.WithMaxDuration(time.Minute*5, retry.NewConstant(time.Second*5)), func(ctx context.Context) error {
-		kubeClient, err := util.GetKubeClient(ctx, cfg, dp)
-		if err != nil {
-			return retry.RetryableError(err)
+	if err := retry.Do(ctx, retry.WithMaxDuration(time.Minute*5, retry.NewConstant(time.Second*5)), func(ctx context.Context) error {
+		kubeClient, diags := getKubeClient(ctx, cfg, dp)
+		if diags.HasError() {
+			return retry.RetryableError(util.DiagnosticsError(diags))
 		}
 
-		dpmanagerDeployment := &appsv1.Deployment{ObjectMeta: v1.ObjectMeta{Name: "dp-manager", Namespace: "deltastream"}}
-		if err = kubeClient.Get(ctx, client.ObjectKeyFromObject(dpmanagerDeployment), dpmanagerDeployment); err != nil {
+		dpmanagerDeployment := &appsv1.Deployment{ObjectMeta: v1.ObjectMeta{Name: "dp-manager", Namespace: clusterConfig.Namespaces().DeltaStream}}
+		if err := kubeClient.Get(ctx, client.ObjectKeyFromObject(dpmanagerDeployment), dpmanagerDeployment); err != nil {
 			return retry.RetryableError(err)
 		}
 
@@ -73,7 +81,7 @@ func deployCustomCredentialsContiner(ctx context.Context, cfg aws.Config, dp aws
 		}
 		dpmanagerDeployment.Spec.Template.Annotations["dataplane.deltastream.io/rollout"] = time.Now().String()
 
-		if err = kubeClient.Update(ctx, dpmanagerDeployment); err != nil {
+		if err := kubeClient.Update(ctx, dpmanagerDeployment); err != nil {
 			return retry.RetryableError(err)
 		}
 		return nil
@@ -82,5 +90,105 @@ func deployCustomCredentialsContiner(ctx context.Context, cfg aws.Config, dp aws
 		return
 	}
 
+	rolloutTimeout, err := time.ParseDuration(clusterConfig.CustomCredentialsRolloutTimeout.ValueString())
+	if err != nil {
+		d.AddError("invalid custom credentials rollout timeout", err.Error())
+		return
+	}
+	d.Append(waitDeploymentRollout(ctx, cfg, dp, getKubeClient, clusterConfig.Namespaces().DeltaStream, "dp-manager", rolloutTimeout)...)
+	return
+}
+
+// waitDeploymentRollout waits until the latest generation of a deployment is observed and all of its replicas are
+// updated and available. On timeout the waiting reasons of the deployment's containers are reported.
+func waitDeploymentRollout(ctx context.Context, cfg aws.Config, dp awsconfig.AWSDataplane, getKubeClient kubeClientFactory, namespace, name string, timeout time.Duration) (d diag.Diagnostics) {
+	deployment := &appsv1.Deployment{}
+	tflog.Debug(ctx, "waiting for deployment rollout", map[string]any{"deployment": namespace + "/" + name, "timeout": timeout.String()})
+	err := retry.Do(ctx, retry.WithMaxDuration(timeout, retry.NewConstant(time.Second*5)), func(ctx context.Context) error {
+		kubeClient, diags := getKubeClient(ctx, cfg, dp)
+		if diags.HasError() {
+			return retry.RetryableError(util.DiagnosticsError(diags))
+		}
+
+		if err := kubeClient.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, deployment); err != nil {
+			return retry.RetryableError(err)
+		}
+
+		replicas := ptr.Deref(deployment.Spec.Replicas, 1)
+		status := deployment.Status
+		switch {
+		case status.ObservedGeneration < deployment.Generation:
+			return retry.RetryableError(fmt.Errorf("waiting for generation %d to be observed", deployment.Generation))
+		case status.UpdatedReplicas < replicas:
+			return retry.RetryableError(fmt.Errorf("%d of %d replicas updated", status.UpdatedReplicas, replicas))
+		case status.Replicas > status.UpdatedReplicas:
+			return retry.RetryableError(fmt.Errorf("%d old replicas pending termination", status.Replicas-status.UpdatedReplicas))
+		case status.AvailableReplicas < replicas:
+			return retry.RetryableError(fmt.Errorf("%d of %d updated replicas available", status.AvailableReplicas, replicas))
+		}
+		return nil
+	})
+	if err == nil {
+		tflog.Debug(ctx, "deployment rolled out", map[string]any{"deployment": namespace + "/" + name})
+		return
+	}
+
+	detail := err.Error()
+	if reasons := containerWaitingReasons(ctx, cfg, dp, getKubeClient, deployment); len(reasons) > 0 {
+		detail += "\n\ncontainers waiting:\n  " + strings.Join(reasons, "\n  ")
+	}
+	d.AddError("timeout waiting for "+name+" rollout", detail)
 	return
 }
+
+// containerWaitingReasons lists the containers of a deployment's pods that are waiting, with the reason and message
+// reported by the kubelet. Lookup failures are ignored.
+func containerWaitingReasons(ctx context.Context, cfg aws.Config, dp awsconfig.AWSDataplane, getKubeClient kubeClientFactory, deployment *appsv1.Deployment) []string {
+	reasons := []string{}
+	if deployment.Spec.Selector == nil {
+		return reasons
+	}
+	kubeClient, diags := getKubeClient(ctx, cfg, dp)
+	if diags.HasError() {
+		return reasons
+	}
+	selector, err := v1.LabelSelectorAsSelector(deployment.Spec.Selector)
+	if err != nil {
+		return reasons
+	}
+
+	pods := corev1.PodList{}
+	if err := kubeClient.List(ctx, &pods, client.InNamespace(deployment.Namespace), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return reasons
+	}
+	for _, pod := range pods.Items {
+		statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+		for _, cs := range statuses {
+			if cs.State.Waiting == nil || cs.State.Waiting.Reason == "" {
+				continue
+			}
+			reasons = append(reasons, fmt.Sprintf("%s/%s: %s %s", pod.Name, cs.Name, cs.State.Waiting.Reason, cs.State.Waiting.Message))
+		}
+	}
+	sort.Strings(reasons)
+	return reasons
+}
+
+// parseImageReference splits an image reference into its repository and either its tag or digest. References without
+// a tag or digest resolve to the latest tag.
+func parseImageReference(image string) (repository, tag, digest string, err error) {
+	named, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	repository = reference.FamiliarName(named)
+	if digested, ok := named.(reference.Digested); ok {
+		return repository, "", digested.Digest().String(), nil
+	}
+	tagged, ok := reference.TagNameOnly(named).(reference.Tagged)
+	if !ok {
+		return "", "", "", fmt.Errorf("unable to determine tag of image %s", image)
+	}
+	return repository, tagged.Tag(), "", nil
+}
@@ -0,0 +1,42 @@
+// Copyright (c) DeltaStream, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package aws
+
+import "testing"
+
+func TestParseImageReference(t *testing.T) {
+	const digest = "sha256:2cf2ab62a7a0ac3a6fd6e8e0cd0b0ba8f4bfd8c8b8e0e0f4f9a4f0f4e2b6a0c1"
+
+	tests := []struct {
+		image      string
+		repository string
+		tag        string
+		digest     string
+		wantErr    bool
+	}{
+		{image: "custom-credentials:1.0.0", repository: "custom-credentials", tag: "1.0.0"},
+		{image: "custom-credentials", repository: "custom-credentials", tag: "latest"},
+		{image: "registry:5000/team/custom-credentials:1.0.0", repository: "registry:5000/team/custom-credentials", tag: "1.0.0"},
+		{image: "registry:5000/team/custom-credentials", repository: "registry:5000/team/custom-credentials", tag: "latest"},
+		{image: "123456789012.dkr.ecr.us-west-2.amazonaws.com/custom-credentials@" + digest, repository: "123456789012.dkr.ecr.us-west-2.amazonaws.com/custom-credentials", digest: digest},
+		{image: "registry:5000/custom-credentials:1.0.0@" + digest, repository: "registry:5000/custom-credentials", digest: digest},
+		{image: "Invalid/Image:tag", wantErr: true},
+		{image: "custom-credentials:", wantErr: true},
+		{image: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.image, func(t *testing.T) {
+			repository, tag, digest, err := parseImageReference(tt.image)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseImageReference(%q) error = %v, wantErr %v", tt.image, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if repository != tt.repository || tag != tt.tag || digest != tt.digest {
+				t.Errorf("parseImageReference(%q) = (%q, %q, %q), want (%q, %q, %q)", tt.image, repository, tag, digest, tt.repository, tt.tag, tt.digest)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright (c) DeltaStream, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package aws
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	corev1 "k8s.io/api/core/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+
+	"github.com/deltastreaminc/terraform-provider-dataplane/internal/config"
+	awsconfig "github.com/deltastreaminc/terraform-provider-dataplane/internal/deltastream/aws/config"
+	"github.com/deltastreaminc/terraform-provider-dataplane/internal/deltastream/aws/util"
+)
+
+var _ datasource.DataSource = &AWSDataplaneClusterSettingsDataSource{}
+var _ datasource.DataSourceWithConfigure = &AWSDataplaneClusterSettingsDataSource{}
+
+func NewAWSDataplaneClusterSettingsDataSource() datasource.DataSource {
+	return &AWSDataplaneClusterSettingsDataSource{}
+}
+
+type AWSDataplaneClusterSettingsDataSource struct {
+	settings util.ClientSettings
+}
+
+func (d *AWSDataplaneClusterSettingsDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = awsconfig.ClusterSettingsDataSourceSchema
+}
+
+func (d *AWSDataplaneClusterSettingsDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	cfg, ok := req.ProviderData.(*config.DataplaneResourceData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *DeltaStreamProviderCfg, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	d.settings = clientSettings(cfg)
+}
+
+func (d *AWSDataplaneClusterSettingsDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_aws_cluster_settings"
+}
+
+func (d *AWSDataplaneClusterSettingsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data awsconfig.AWSDataplaneClusterSettings
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	assumeRole, diags := data.AssumeRoleData(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	cfg, diags := util.GetAwsConfigForAssumeRole(ctx, d.settings, assumeRole)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	stack := awsconfig.StackOrDefault(data.Stack)
+
+	clusterName := util.KubeClusterName(data.InfraId.ValueString(), stack, data.EksResourceId.ValueString(), ptr.Deref(data.ClusterIndex.ValueInt64Pointer(), 0))
+	kubeClient, err := util.GetKubeClientForCluster(ctx, d.settings, cfg, clusterName, data.KubeApiEndpointOverride)
+	if err != nil {
+		resp.Diagnostics.AddError("error getting kube client", err.Error())
+		return
+	}
+
+	secret := &corev1.Secret{}
+	if err := kubeClient.Get(ctx, k8stypes.NamespacedName{Namespace: data.Namespaces().ClusterConfig, Name: "cluster-settings"}, secret); err != nil {
+		resp.Diagnostics.AddError("error reading cluster settings", err.Error())
+		return
+	}
+
+	data.Settings, diags = basetypes.NewMapValueFrom(ctx, basetypes.StringType{}, util.RedactSettings(secret.Data))
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,96 @@
+// Copyright (c) DeltaStream, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package aws
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+
+	"github.com/deltastreaminc/terraform-provider-dataplane/internal/config"
+	awsconfig "github.com/deltastreaminc/terraform-provider-dataplane/internal/deltastream/aws/config"
+	"github.com/deltastreaminc/terraform-provider-dataplane/internal/deltastream/aws/util"
+)
+
+var _ datasource.DataSource = &AWSDataplaneImagesDataSource{}
+var _ datasource.DataSourceWithConfigure = &AWSDataplaneImagesDataSource{}
+
+func NewAWSDataplaneImagesDataSource() datasource.DataSource {
+	return &AWSDataplaneImagesDataSource{}
+}
+
+type AWSDataplaneImagesDataSource struct {
+	settings util.ClientSettings
+}
+
+func (d *AWSDataplaneImagesDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = awsconfig.ImagesDataSourceSchema
+}
+
+func (d *AWSDataplaneImagesDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	cfg, ok := req.ProviderData.(*config.DataplaneResourceData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *DeltaStreamProviderCfg, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	d.settings = clientSettings(cfg)
+}
+
+func (d *AWSDataplaneImagesDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_aws_images"
+}
+
+func (d *AWSDataplaneImagesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data awsconfig.AWSDataplaneImages
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	assumeRole, diags := data.AssumeRoleData(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	cfg, diags := util.GetAwsConfigForAssumeRole(ctx, d.settings, assumeRole)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	bucketName, err := awsconfig.PackagesBucketName(awsconfig.StackOrDefault(data.Stack))
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("stack"), "invalid stack", err.Error())
+		return
+	}
+
+	imgList, diags := getImageList(ctx, packagesS3Client(cfg), bucketName, data.ProductVersion.ValueString())
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.Images, diags = basetypes.NewListValueFrom(ctx, basetypes.StringType{}, imgList.Images)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	data.ExecEngineVersion = basetypes.NewStringValue(imgList.ExecEngineVersion)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
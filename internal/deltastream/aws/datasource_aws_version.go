@@ -0,0 +1,126 @@
+// Copyright (c) DeltaStream, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package aws
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+
+	"github.com/deltastreaminc/terraform-provider-dataplane/internal/config"
+	awsconfig "github.com/deltastreaminc/terraform-provider-dataplane/internal/deltastream/aws/config"
+	"github.com/deltastreaminc/terraform-provider-dataplane/internal/deltastream/aws/util"
+)
+
+var _ datasource.DataSource = &AWSDataplaneVersionDataSource{}
+var _ datasource.DataSourceWithConfigure = &AWSDataplaneVersionDataSource{}
+
+func NewAWSDataplaneVersionDataSource() datasource.DataSource {
+	return &AWSDataplaneVersionDataSource{}
+}
+
+type AWSDataplaneVersionDataSource struct {
+	settings util.ClientSettings
+}
+
+func (d *AWSDataplaneVersionDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = awsconfig.VersionDataSourceSchema
+}
+
+func (d *AWSDataplaneVersionDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	cfg, ok := req.ProviderData.(*config.DataplaneResourceData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *DeltaStreamProviderCfg, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	d.settings = clientSettings(cfg)
+}
+
+func (d *AWSDataplaneVersionDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_aws_version"
+}
+
+func (d *AWSDataplaneVersionDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data awsconfig.AWSDataplaneVersion
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	assumeRole, diags := data.AssumeRoleData(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	cfg, diags := util.GetAwsConfigForAssumeRole(ctx, d.settings, assumeRole)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	stack := awsconfig.StackOrDefault(data.Stack)
+	bucketName, err := awsconfig.PackagesBucketName(stack)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("stack"), "invalid stack", err.Error())
+		return
+	}
+	s3client := packagesS3Client(cfg)
+
+	versions := []string{}
+	paginator := s3.NewListObjectsV2Paginator(s3client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(bucketName),
+		Prefix: aws.String(imageListPrefix),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			resp.Diagnostics.AddError("error listing product versions", util.AwsErrorDetail(err, util.Remediation{Action: "s3:ListBucket"}))
+			return
+		}
+		for _, obj := range page.Contents {
+			key := aws.ToString(obj.Key)
+			if !strings.HasSuffix(key, ".yaml") {
+				continue
+			}
+			versions = append(versions, strings.TrimSuffix(strings.TrimPrefix(key, imageListPrefix), ".yaml"))
+		}
+	}
+	sort.Strings(versions)
+
+	data.Versions, diags = basetypes.NewListValueFrom(ctx, basetypes.StringType{}, versions)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.ExecEngineVersion = basetypes.NewStringNull()
+	if !(data.ProductVersion.IsNull() || data.ProductVersion.IsUnknown()) {
+		imgList, diags := getImageList(ctx, s3client, bucketName, data.ProductVersion.ValueString())
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		data.ExecEngineVersion = basetypes.NewStringValue(imgList.ExecEngineVersion)
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -5,8 +5,13 @@ package aws
 
 import (
 	"context"
+	"crypto/sha256"
 	_ "embed"
+	"encoding/hex"
 	"fmt"
+	"regexp"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -15,6 +20,8 @@ import (
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/sethvargo/go-retry"
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/ptr"
@@ -33,53 +40,129 @@ var dataPlaneTemplate []byte
 //go:embed assets/cluster-config/platform.yaml.tmpl
 var platformTemplate []byte
 
-func installDeltaStream(ctx context.Context, cfg aws.Config, dp awsconfig.AWSDataplane) (d diag.Diagnostics) {
+const (
+	installedProviderVersionAnnotation = "dataplane.deltastream.io/installed-provider-version"
+	installedProductVersionAnnotation  = "dataplane.deltastream.io/installed-product-version"
+	installedManifestsHashAnnotation   = "dataplane.deltastream.io/installed-manifests-hash"
+	installedSettingsHashAnnotation    = "dataplane.deltastream.io/installed-settings-hash"
+)
+
+func installDeltaStream(ctx context.Context, cfg aws.Config, dp awsconfig.AWSDataplane, getKubeClient kubeClientFactory, infraVersion string, defaultTags map[string]string) (d diag.Diagnostics) {
 	clusterConfig, diags := dp.ClusterConfigurationData(ctx)
 	d.Append(diags...)
 	if d.HasError() {
 		return
 	}
 
-	kubeClient, err := util.GetKubeClient(ctx, cfg, dp)
-	if err != nil {
-		d.AddError("error getting kube client", err.Error())
+	namespaces := clusterConfig.Namespaces()
+
+	kubeClient, diags := getKubeClient(ctx, cfg, dp)
+	d.Append(diags...)
+	if d.HasError() {
 		return
 	}
 
-	d.Append(UpdateDeploymentConfig(ctx, cfg, dp)...)
+	d.Append(UpdateDeploymentConfig(ctx, cfg, dp, defaultTags, kubeClient.DryRun())...)
 	if d.HasError() {
 		return
 	}
 
-	d.Append(util.RenderAndApplyTemplate(ctx, kubeClient, "flux", fluxManifestTemplate, map[string]string{
+	sourceUrl, sourceRef, sourceProvider := manifestsSource(cfg, clusterConfig)
+	fluxData := map[string]string{
 		"EksReaderRoleArn": clusterConfig.EcrReadonlyRoleArn.ValueString(),
 		"Region":           cfg.Region,
 		"AccountID":        clusterConfig.AccountId.ValueString(),
-	})...)
-	if d.HasError() {
+	}
+	// the source controller may only reach the ECR registry of the account, mirrors are allowed explicitly
+	if host, port := manifestsSourceHost(sourceUrl); host != ecrRegistry(clusterConfig.AccountId.ValueString(), cfg.Region) {
+		fluxData["ManifestsSourceHost"] = host
+		fluxData["ManifestsSourcePort"] = port
+	}
+	platformData := map[string]string{
+		"ManifestsSourceURL":      sourceUrl,
+		"ManifestsSourceRef":      sourceRef,
+		"ManifestsSourceProvider": sourceProvider,
+		"ClusterConfigNamespace":  namespaces.ClusterConfig,
+	}
+	dataPlaneData := map[string]string{
+		"ManifestsSourceURL":      sourceUrl,
+		"ManifestsSourceRef":      sourceRef,
+		"ManifestsSourceProvider": sourceProvider,
+		"ClusterConfigNamespace":  namespaces.ClusterConfig,
+	}
+	manifestsHash := templateInputsHash(fluxData, platformData, dataPlaneData)
+
+	// manifests are only re-applied, and flux restarted, when the provider or product version, the template inputs or
+	// the cluster settings changed since the last successful install. Flux only picks up new cluster settings when it
+	// is restarted.
+	clusterSettings := &corev1.Secret{}
+	if err := kubeClient.Get(ctx, client.ObjectKey{Namespace: namespaces.ClusterConfig, Name: "cluster-settings"}, clusterSettings); err != nil {
+		// a dry run does not create the cluster settings of a new dataplane
+		if !(kubeClient.DryRun() && k8serrors.IsNotFound(err)) {
+			d.AddError("error reading cluster settings", err.Error())
+			return
+		}
+	}
+	settingsHash := secretDataHash(clusterSettings.Data)
+	annotations := clusterSettings.GetAnnotations()
+	if annotations[installedProviderVersionAnnotation] == infraVersion &&
+		annotations[installedProductVersionAnnotation] == clusterConfig.ProductVersion.ValueString() &&
+		annotations[installedManifestsHashAnnotation] == manifestsHash &&
+		annotations[installedSettingsHashAnnotation] == settingsHash {
+		tflog.Info(ctx, "DeltaStream already installed at the requested version, skipping manifest apply", map[string]any{
+			"provider version": infraVersion,
+			"product version":  clusterConfig.ProductVersion.ValueString(),
+		})
 		return
 	}
 
-	d.Append(util.RenderAndApplyTemplate(ctx, kubeClient, "platform", platformTemplate, map[string]string{
-		"Region":         cfg.Region,
-		"AccountID":      clusterConfig.AccountId.ValueString(),
-		"ProductVersion": clusterConfig.ProductVersion.ValueString(),
-	})...)
+	// with ordered upgrades the top level kustomizations are suspended while the new manifests are applied, so Flux
+	// reconciles the upgrade once rather than every intermediate state. They are resumed even if the apply fails.
+	if clusterConfig.OrderedUpgrade.ValueBool() && !kubeClient.DryRun() {
+		orderedKustomizations := []string{"infra", "data-plane"}
+		for _, name := range orderedKustomizations {
+			d.Append(suspendKustomization(ctx, kubeClient, namespaces.ClusterConfig, name)...)
+		}
+		defer func() {
+			for _, name := range orderedKustomizations {
+				d.Append(resumeKustomization(ctx, kubeClient, namespaces.ClusterConfig, name)...)
+			}
+		}()
+		if d.HasError() {
+			return
+		}
+	}
+
+	d.Append(util.RenderAndApplyTemplate(ctx, kubeClient, "flux", fluxManifestTemplate, fluxData, util.TemplateOptions{})...)
 	if d.HasError() {
 		return
 	}
 
-	d.Append(util.RenderAndApplyTemplate(ctx, kubeClient, "data plane", dataPlaneTemplate, map[string]string{
-		"Region":         cfg.Region,
-		"AccountID":      clusterConfig.AccountId.ValueString(),
-		"ProductVersion": clusterConfig.ProductVersion.ValueString(),
-	})...)
+	// the platform and data plane templates contain Flux resources that can only be applied once the CRDs are served
+	crdEstablishedTimeout, err := time.ParseDuration(clusterConfig.CrdEstablishedTimeout.ValueString())
+	if err != nil {
+		d.AddError("invalid CRD established timeout", err.Error())
+		return
+	}
+	if !kubeClient.DryRun() {
+		d.Append(util.WaitForCRDsEstablished(ctx, kubeClient, "toolkit.fluxcd.io", crdEstablishedTimeout)...)
+		if d.HasError() {
+			return
+		}
+	}
+
+	d.Append(util.RenderAndApplyTemplate(ctx, kubeClient, "platform", platformTemplate, platformData, util.TemplateOptions{})...)
 	if d.HasError() {
 		return
 	}
 
+	d.Append(util.RenderAndApplyTemplate(ctx, kubeClient, "data plane", dataPlaneTemplate, dataPlaneData, util.TemplateOptions{Prune: true, InventoryNamespace: namespaces.KubeSystem})...)
+	if d.HasError() || kubeClient.DryRun() {
+		return
+	}
+
 	deployments := appsv1.DeploymentList{}
-	if err := kubeClient.List(ctx, &deployments, client.InNamespace("flux-system")); err != nil {
+	if err := kubeClient.List(ctx, &deployments, client.InNamespace(namespaces.FluxSystem)); err != nil {
 		d.AddError("error listing flux-system deployments", err.Error())
 		return
 	}
@@ -88,8 +171,8 @@ func installDeltaStream(ctx context.Context, cfg aws.Config, dp awsconfig.AWSDat
 		if deployment.Spec.Template.Annotations == nil {
 			deployment.Spec.Template.Annotations = map[string]string{}
 		}
-		deployment.Spec.Template.Annotations["io.deltastream.tf-deltastream/restartedAt"] = time.Now().Format(time.RFC3339)
-		if err := retry.Do(ctx, retrylimits, func(ctx context.Context) error {
+		deployment.Spec.Template.Annotations[restartedAtAnnotation] = time.Now().Format(time.RFC3339)
+		if err := retry.Do(ctx, kubeClient.RetryBackoff(), func(ctx context.Context) error {
 			return retry.RetryableError(kubeClient.Update(ctx, &deployment))
 		}); err != nil {
 			d.AddError("error updating deployment "+deployment.Name, err.Error())
@@ -97,27 +180,115 @@ func installDeltaStream(ctx context.Context, cfg aws.Config, dp awsconfig.AWSDat
 		}
 	}
 
+	patch := client.MergeFrom(clusterSettings.DeepCopy())
+	if clusterSettings.Annotations == nil {
+		clusterSettings.Annotations = map[string]string{}
+	}
+	clusterSettings.Annotations[installedProviderVersionAnnotation] = infraVersion
+	clusterSettings.Annotations[installedProductVersionAnnotation] = clusterConfig.ProductVersion.ValueString()
+	clusterSettings.Annotations[installedManifestsHashAnnotation] = manifestsHash
+	clusterSettings.Annotations[installedSettingsHashAnnotation] = settingsHash
+	if err := kubeClient.Patch(ctx, clusterSettings, patch); err != nil {
+		d.AddError("error recording installed versions", err.Error())
+		return
+	}
+
+	return
+}
+
+// ecrRegistryPattern matches repositories of private ECR registries.
+var ecrRegistryPattern = regexp.MustCompile(`^[0-9]{12}\.dkr\.ecr\.[a-z0-9-]+\.amazonaws\.com(\.cn)?(/|$)`)
+
+// manifestsSource returns the OCI repository prefix and tag the Flux sources of the platform and data plane manifests
+// are pulled from, and the Flux provider authenticating to it. ECR repositories are accessed with the node role, other
+// registries anonymously.
+func manifestsSource(cfg aws.Config, clusterConfig awsconfig.ClusterConfiguration) (url, ref, provider string) {
+	url = "oci://" + ecrRegistry(clusterConfig.AccountId.ValueString(), cfg.Region) + "/deltastreaminc/oci"
+	if !(clusterConfig.ManifestsSourceUrl.IsNull() || clusterConfig.ManifestsSourceUrl.IsUnknown()) {
+		url = strings.TrimSuffix(clusterConfig.ManifestsSourceUrl.ValueString(), "/")
+	}
+	ref = clusterConfig.ProductVersion.ValueString()
+	if !(clusterConfig.ManifestsSourceRef.IsNull() || clusterConfig.ManifestsSourceRef.IsUnknown()) {
+		ref = clusterConfig.ManifestsSourceRef.ValueString()
+	}
+	provider = "generic"
+	if ecrRegistryPattern.MatchString(strings.TrimPrefix(url, "oci://")) {
+		provider = "aws"
+	}
 	return
 }
 
-func waitKustomizations(ctx context.Context, cfg aws.Config, dp awsconfig.AWSDataplane) (d diag.Diagnostics) {
-	err := retry.Do(ctx, retry.WithMaxDuration(time.Minute*30, retry.NewConstant(10*time.Second)), func(ctx context.Context) error {
-		kubeClient, err := util.GetKubeClient(ctx, cfg, dp)
-		if err != nil {
-			return retry.RetryableError(err)
+// manifestsSourceHost returns the host and port of the registry of an OCI repository URL.
+func manifestsSourceHost(url string) (host, port string) {
+	host, _, _ = strings.Cut(strings.TrimPrefix(url, "oci://"), "/")
+	if h, p, ok := strings.Cut(host, ":"); ok {
+		return h, p
+	}
+	return host, "443"
+}
+
+// templateInputsHash returns a stable hash of the data used to render the DeltaStream manifests.
+func templateInputsHash(inputs ...map[string]string) string {
+	h := sha256.New()
+	for _, input := range inputs {
+		keys := make([]string, 0, len(input))
+		for k := range input {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			fmt.Fprintf(h, "%s=%s\n", k, input[k])
+		}
+		h.Write([]byte("---\n"))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// secretDataHash returns a stable hash of the data of a secret.
+func secretDataHash(data map[string][]byte) string {
+	values := make(map[string]string, len(data))
+	for k, v := range data {
+		values[k] = string(v)
+	}
+	return templateInputsHash(values)
+}
+
+func waitKustomizations(ctx context.Context, cfg aws.Config, dp awsconfig.AWSDataplane, getKubeClient kubeClientFactory) (d diag.Diagnostics) {
+	clusterConfig, diags := dp.ClusterConfigurationData(ctx)
+	d.Append(diags...)
+	if d.HasError() {
+		return
+	}
+
+	reconcileTimeout, err := time.ParseDuration(clusterConfig.KustomizationReconcileTimeout.ValueString())
+	if err != nil {
+		d.AddError("invalid kustomization reconcile timeout", err.Error())
+		return
+	}
+
+	// kustomizations that reported Ready=False on the last check, keyed by name
+	failedKustomizations := map[string]string{}
+	err = retry.Do(ctx, retry.WithMaxDuration(reconcileTimeout, retry.NewConstant(10*time.Second)), func(ctx context.Context) error {
+		kubeClient, diags := getKubeClient(ctx, cfg, dp)
+		if diags.HasError() {
+			return retry.RetryableError(util.DiagnosticsError(diags))
 		}
 
 		kustomizations := kustomizev1.KustomizationList{}
-		if err := kubeClient.List(ctx, &kustomizations, client.InNamespace("cluster-config")); err != nil {
+		if err := kubeClient.List(ctx, &kustomizations, client.InNamespace(clusterConfig.Namespaces().ClusterConfig)); err != nil {
 			return err
 		}
 
+		failedKustomizations = map[string]string{}
 		notReadyKustomizations := map[string]string{}
 		for _, kustomization := range kustomizations.Items {
 			if meta.IsStatusConditionTrue(kustomization.Status.Conditions, "Ready") {
 				continue
 			}
 			notReadyKustomizations[kustomization.Name] = ptr.Deref(meta.FindStatusCondition(kustomization.Status.Conditions, "Ready"), metav1.Condition{Message: "Not ready"}).Message
+			if meta.IsStatusConditionFalse(kustomization.Status.Conditions, "Ready") {
+				failedKustomizations[kustomization.Name] = notReadyKustomizations[kustomization.Name]
+			}
 		}
 
 		summary := "services not ready: \n"
@@ -132,6 +303,14 @@ func waitKustomizations(ctx context.Context, cfg aws.Config, dp awsconfig.AWSDat
 		return nil
 	})
 	if err != nil {
+		names := make([]string, 0, len(failedKustomizations))
+		for name := range failedKustomizations {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			d.AddError("kustomization "+name+" failed to reconcile", failedKustomizations[name])
+		}
 		d.AddError("timeout waiting for services to start", err.Error())
 	}
 	return
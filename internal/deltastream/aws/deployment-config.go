@@ -6,13 +6,15 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"html/template"
+	"net"
 	"strings"
+	"text/template"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
 	"github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"k8s.io/utils/ptr"
 
 	awsconfig "github.com/deltastreaminc/terraform-provider-dataplane/internal/deltastream/aws/config"
@@ -23,105 +25,139 @@ const deploymentConfigTmpl = `
 {
   "vault": {
     "kms": {
-      "key_id": "{{ .KmsKeyId }}",
-      "region": "{{ .Region }}"
+      "key_id": {{ toJson .KmsKeyId }},
+      "region": {{ toJson .Region }}
     },
     "dynamodb": {
-      "table": "{{ .DynamoDbTable }}",
-      "region": "{{ .Region }}"
+      "table": {{ toJson .DynamoDbTable }},
+      "region": {{ toJson .Region }}
     }
   },
   "postgres": {
-    "username": "{{ .Rds.Username }}",
-    "password": "{{ .Rds.Password }}",
-    "database": "{{ .Rds.Database }}",
+    "username": {{ toJson .Rds.Username }},
+{{- if .RdsIamRoleARN }}
+    "authMode": "iam",
+    "roleARN": {{ toJson .RdsIamRoleARN }},
+{{- else }}
+    "password": {{ toJson .Rds.Password }},
+{{- end }}
+    "database": {{ toJson .Rds.Database }},
     "sslMode": "require",
-    "host": "{{ .Rds.Host }}",
+    "host": {{ toJson .Rds.Host }},
     "port": {{ .Rds.Port }}
   },
   "kafka": {
-    "hosts": "{{ .KafkaBrokerList }}",
-    "bootstrapBrokersIam": "{{ .KafkaBrokerList }}",
-    "brokerListenerPorts": "{{ .KafkaBrokerListenerPorts }}",
+    "hosts": {{ toJson .KafkaBrokerList }},
+    "brokerListenerPorts": {{ toJson .KafkaBrokerListenerPorts }},
     "enableTLS": true,
     "topicReplicas": 3,
-    "region": "{{ .Region }}",
-    "roleARN": "{{ .KafkaRoleARN }}",
-    "externalId": "{{ .KafkaRoleExternalId }}"
+    "region": {{ toJson .Region }},
+{{- if .KafkaScram }}
+    "saslMechanism": "SCRAM-SHA-512",
+    "username": {{ toJson .KafkaScram.Username }},
+    "password": {{ toJson .KafkaScram.Password }}
+{{- else }}
+    "bootstrapBrokersIam": {{ toJson .KafkaBrokerList }},
+    "roleARN": {{ toJson .KafkaRoleARN }},
+    "externalId": {{ toJson .KafkaRoleExternalId }}
+{{- end }}
   },
+{{- if .ControlPlaneKafkaBrokerList }}
   "cpKafka": {
-    "hosts": "{{ .ControlPlaneKafkaBrokerList }}",
-    "bootstrapBrokersIam": "{{ .ControlPlaneKafkaBrokerList }}",
-    "brokerListenerPorts": "{{ .ControlPlaneKafkaBrokerListenerPorts }}",
+    "hosts": {{ toJson .ControlPlaneKafkaBrokerList }},
+    "bootstrapBrokersIam": {{ toJson .ControlPlaneKafkaBrokerList }},
+    "brokerListenerPorts": {{ toJson .ControlPlaneKafkaBrokerListenerPorts }},
     "topicReplicas": 3,
-    "region": "{{ .ControlPlaneRegion }}"
+    "region": {{ toJson .ControlPlaneRegion }}
   },
+{{- end }}
   "hostnames": {
-    "dpAPIHostname": "{{ .ApiHostname }}"
+    "dpAPIHostname": {{ toJson .ApiHostname }}
   },
   "googleOAuth": {
-    "clientID": "{{ .DSSecret.GoogleClientID }}",
-    "clientSecret": "{{ .DSSecret.GoogleClientSecret }}"
+    "clientID": {{ toJson .DSSecret.GoogleClientID }},
+    "clientSecret": {{ toJson .DSSecret.GoogleClientSecret }}
   },
   "s3": {
     "execEngineBucket": {
-      "name": "{{ .ProductArtifactsBucket }}",
-      "region": "{{ .Region }}"
+      "name": {{ toJson .ProductArtifactsBucket }},
+      "region": {{ toJson .Region }}
     },
     "serdeDescriptorBucket": {
-      "name": "{{ .SerdeBucket }}",
-      "region": "{{ .SerdeBucketRegion }}"
+      "name": {{ toJson .SerdeBucket }},
+      "region": {{ toJson .SerdeBucketRegion }}
     },
     "flinkQueryStateBucket": {
-      "name": "{{ .WorkloadStateBucket }}",
-      "region": "{{ .Region }}"
+      "name": {{ toJson .WorkloadStateBucket }},
+      "region": {{ toJson .Region }}
     },
     "lokiRulerStorageBucket": {
-      "name": "{{ .O11yBucket }}",
-      "region": "{{ .Region }}"
+      "name": {{ toJson .O11yBucket }},
+      "region": {{ toJson .O11yBucketRegion }}
     },
     "lokiStorageBucket": {
-      "name": "{{ .O11yBucket }}",
-      "region": "{{ .Region }}"
+      "name": {{ toJson .O11yBucket }},
+      "region": {{ toJson .O11yBucketRegion }}
     },
     "lokiAdminBucket": {
-      "name": "{{ .O11yBucket }}",
-      "region": "{{ .Region }}"
+      "name": {{ toJson .O11yBucket }},
+      "region": {{ toJson .O11yBucketRegion }}
     },
     "prometheusStorageBucket": {
-      "name": "{{ .O11yBucket }}",
-      "region": "{{ .Region }}"
+      "name": {{ toJson .O11yBucket }},
+      "region": {{ toJson .O11yBucketRegion }}
     },
     "tempoStorageBucket": {
-      "name": "{{ .O11yBucket }}",
-      "region": "{{ .Region }}"
-    },
+      "name": {{ toJson .O11yBucket }},
+      "region": {{ toJson .O11yBucketRegion }}
+    }{{ if .Cw2LokiSqsURL }},
     "cw2loki": {
-      "name": "{{ .O11yBucket }}",
-      "region": "{{ .Region }}",
+      "name": {{ toJson .O11yBucket }},
+      "region": {{ toJson .O11yBucketRegion }},
       "bucket_prefix" : "cw2loki"
-    }
+    }{{ end }}
   },
   "kube": {
     "storageClass": "gp3"
   },
   "slack": {
-    "token": "{{ .DSSecret.SlackToken }}",
-    "channel": "{{ .DSSecret.SlackChannel }}",
-    "pingUser": "{{ .DSSecret.SlackPingUser }}"
+    "token": {{ toJson .DSSecret.SlackToken }},
+    "channel": {{ toJson .DSSecret.SlackChannel }},
+    "pingUser": {{ toJson .DSSecret.SlackPingUser }}
   },
   "pagerduty": {
-    "serviceKey": "{{ .DSSecret.PagerdutyServiceKey }}"
-  },
+    "serviceKey": {{ toJson .DSSecret.PagerdutyServiceKey }}
+  }{{ if .Cw2LokiSqsURL }},
   "cw2loki": {
-    "eksClusterName": "{{ .KubeClusterName }}",
-    "mskClusterName": "{{ .KafkaClusterName }}",
-    "rdsName": "{{ .RdsClusterName}}",
-    "importBucketAccount": "{{ .AccountID }}",
-    "sqsURL": "{{ .Cw2LokiSqsURL }}"
-  }
+    "eksClusterName": {{ toJson .KubeClusterName }},
+    "mskClusterName": {{ toJson .KafkaClusterName }},
+    "rdsName": {{ toJson .RdsClusterName }},
+    "importBucketAccount": {{ toJson .AccountID }},
+    "sqsURL": {{ toJson .Cw2LokiSqsURL }}
+  }{{ end }}
 }`
 
+// deploymentConfigTemplate renders the deployment config. Interpolated values are JSON encoded so that credentials
+// containing quotes, backslashes or other special characters are passed through unchanged.
+var deploymentConfigTemplate = template.Must(template.New("deploymentConfig").Funcs(template.FuncMap{
+	"toJson": toJson,
+}).Parse(deploymentConfigTmpl))
+
+// toJson encodes a template value as a JSON value, strings are quoted and escaped.
+func toJson(v any) (string, error) {
+	b, err := json.Marshal(v)
+	return string(b), err
+}
+
+// renderDeploymentConfig renders the deployment config document from the template data.
+func renderDeploymentConfig(data map[string]any) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := deploymentConfigTemplate.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
 type DSSecrets struct {
 	GoogleClientID      string `json:"googleClientID"`
 	GoogleClientSecret  string `json:"googleClientSecret"`
@@ -131,6 +167,11 @@ type DSSecrets struct {
 	PagerdutyServiceKey string `json:"pagerdutyServiceKey"`
 }
 
+type KafkaScramCredSecret struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
 type PostgresCredSecret struct {
 	Username string `json:"username"`
 	Password string `json:"password"`
@@ -139,7 +180,7 @@ type PostgresCredSecret struct {
 	Database string `json:"dbClusterIdentifier"`
 }
 
-func UpdateDeploymentConfig(ctx context.Context, cfg aws.Config, dp awsconfig.AWSDataplane) (diags diag.Diagnostics) {
+func UpdateDeploymentConfig(ctx context.Context, cfg aws.Config, dp awsconfig.AWSDataplane, defaultTags map[string]string, dryRun bool) (diags diag.Diagnostics) {
 	config, dg := dp.ClusterConfigurationData(ctx)
 	diags.Append(dg...)
 	if diags.HasError() {
@@ -147,49 +188,92 @@ func UpdateDeploymentConfig(ctx context.Context, cfg aws.Config, dp awsconfig.AW
 	}
 
 	// Get DeltaStream secret with credentials for PagerDuty, Slack, and Google OAuth
+	dsRegion := util.DsRegion(cfg, config)
 	dsCfg := cfg.Copy()
-	dsCfg.Region = config.DsRegion.ValueString()
+	dsCfg.Region = dsRegion
 	dsSecretsmanagerClient := secretsmanager.NewFromConfig(dsCfg)
 	providerSecretArn := fmt.Sprintf("%s:secret:deltastream/%s/dp/aws/%s/deployment/%s/provider-dataplane", util.GetARNForCPService(ctx, cfg, config, "secretsmanager"), config.Stack.ValueString(), cfg.Region, config.InfraId.ValueString())
 	dsSecret, err := dsSecretsmanagerClient.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{
 		SecretId: ptr.To(providerSecretArn),
 	})
-	if err != nil {
-		diags.AddError("unable to read DeltaStream secret "+providerSecretArn, err.Error())
-		return
-	}
-
 	dsSecrets := &DSSecrets{}
-	if err := json.Unmarshal([]byte(ptr.Deref(dsSecret.SecretString, string(dsSecret.SecretBinary))), dsSecrets); err != nil {
-		diags.AddError("unable to unmarshal DeltaStream secret", err.Error())
-		return
-	}
-
-	// Get Postgres credentials
-	secretsmanagerClient := secretsmanager.NewFromConfig(cfg)
-	rdsSecretArn := fmt.Sprintf("%s:secret:deltastream/%s/dp-%s/rds/%s/%s/db/credential-0", util.GetARNForService(ctx, cfg, config, "secretsmanager"), config.Stack.ValueString(), config.InfraId.ValueString(), cfg.Region, config.RdsResourceID.ValueString())
-	rdsCred, err := secretsmanagerClient.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{
-		SecretId: ptr.To(rdsSecretArn),
-	})
 	if err != nil {
-		diags.AddError("unable to read rds credentials "+rdsSecretArn, err.Error())
-		return
+		var resourceNotFoundException *types.ResourceNotFoundException
+		if !errors.As(err, &resourceNotFoundException) {
+			diags.AddError("unable to read DeltaStream secret "+providerSecretArn, util.AwsErrorDetail(err, util.Remediation{Action: "secretsmanager:GetSecretValue"}))
+			return
+		}
+		// integrations such as Slack, PagerDuty and Google OAuth are optional
+		tflog.Warn(ctx, "DeltaStream secret not found, integrations will not be configured", map[string]any{"secret": providerSecretArn})
+	} else {
+		if err := json.Unmarshal([]byte(ptr.Deref(dsSecret.SecretString, string(dsSecret.SecretBinary))), dsSecrets); err != nil {
+			diags.AddError("unable to unmarshal DeltaStream secret", err.Error())
+			return
+		}
 	}
 
+	// Get Postgres credentials, in IAM mode the connection details are configured and no password is read
 	pgCred := &PostgresCredSecret{}
-	if err := json.Unmarshal([]byte(ptr.Deref(rdsCred.SecretString, string(rdsCred.SecretBinary))), pgCred); err != nil {
-		diags.AddError("unable to unmarshal rds credentials", err.Error())
-		return
+	rdsIamRoleArn := ""
+	secretsmanagerClient := secretsmanager.NewFromConfig(cfg)
+	if config.RdsAuthMode.ValueString() == awsconfig.RdsAuthModeIam {
+		if config.RdsIamRoleArn.IsNull() || config.RdsIamRoleArn.ValueString() == "" {
+			diags.AddError("invalid rds configuration", "rds_iam_role_arn is required when rds_auth_mode is iam")
+			return
+		}
+		rdsIamRoleArn = config.RdsIamRoleArn.ValueString()
+		pgCred.Username = config.RdsUsername.ValueString()
+		pgCred.Host = config.RdsHost.ValueString()
+		pgCred.Port = int(config.RdsPort.ValueInt64())
+		pgCred.Database = config.RdsDatabase.ValueString()
+	} else {
+		rdsSecretArn := rdsCredentialsSecretId(ctx, cfg, config)
+		rdsCred, err := secretsmanagerClient.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{
+			SecretId: ptr.To(rdsSecretArn),
+		})
+		if err != nil {
+			var resourceNotFoundException *types.ResourceNotFoundException
+			if errors.As(err, &resourceNotFoundException) {
+				diags.AddError("rds credentials secret not found "+rdsSecretArn, "The RDS credentials secret is required to configure the dataplane: "+err.Error())
+				return
+			}
+			diags.AddError("unable to read rds credentials "+rdsSecretArn, util.AwsErrorDetail(err, util.Remediation{Action: "secretsmanager:GetSecretValue", Attribute: "rds_credentials_secret_id"}))
+			return
+		}
+
+		if err := json.Unmarshal([]byte(ptr.Deref(rdsCred.SecretString, string(rdsCred.SecretBinary))), pgCred); err != nil {
+			diags.AddError("unable to unmarshal rds credentials", err.Error())
+			return
+		}
 	}
-	if strings.Contains(pgCred.Host, ":") {
-		hostPort := strings.Split(pgCred.Host, ":")
-		pgCred.Host = hostPort[0]
+	pgCred.Host = stripPort(pgCred.Host)
+
+	if config.ValidateRdsConnectivity.ValueBool() {
+		diags.Append(checkRdsConnectivity(ctx, pgCred.Host, pgCred.Port, config.ValidateRdsTls.ValueBool())...)
+		if diags.HasError() {
+			return
+		}
 	}
 
-	tmpl, err := template.New("deploymentConfig").Parse(deploymentConfigTmpl)
-	if err != nil {
-		diags.AddError("unable to parse deployment config template", err.Error())
-		return
+	// the settings required by the kafka auth mode are checked by validateConfiguration
+	var kafkaScram *KafkaScramCredSecret
+	if config.KafkaAuthMode.ValueString() == awsconfig.KafkaAuthModeScram {
+		kafkaScramCred, err := secretsmanagerClient.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{
+			SecretId: ptr.To(config.KafkaScramSecret.ValueString()),
+		})
+		if err != nil {
+			diags.AddError("unable to read kafka SCRAM credentials "+config.KafkaScramSecret.ValueString(), util.AwsErrorDetail(err, util.Remediation{Action: "secretsmanager:GetSecretValue", Attribute: "kafka_scram_secret"}))
+			return
+		}
+		kafkaScram = &KafkaScramCredSecret{}
+		if err := json.Unmarshal([]byte(ptr.Deref(kafkaScramCred.SecretString, string(kafkaScramCred.SecretBinary))), kafkaScram); err != nil {
+			diags.AddError("unable to unmarshal kafka SCRAM credentials", err.Error())
+			return
+		}
+		if kafkaScram.Username == "" || kafkaScram.Password == "" {
+			diags.AddError("invalid kafka SCRAM credentials", "secret "+config.KafkaScramSecret.ValueString()+" must contain a username and password")
+			return
+		}
 	}
 
 	kafkaBrokers := []string{}
@@ -204,46 +288,57 @@ func UpdateDeploymentConfig(ctx context.Context, cfg aws.Config, dp awsconfig.AW
 		return
 	}
 
+	// the control plane kafka block is omitted for dataplanes that do not connect to a control plane kafka
 	cpKafkaBrokers := []string{}
-	diags.Append(config.ControlPlaneKafkaHosts.ElementsAs(ctx, &cpKafkaBrokers, false)...)
-	if diags.HasError() {
-		return
+	if !(config.ControlPlaneKafkaHosts.IsNull() || config.ControlPlaneKafkaHosts.IsUnknown()) {
+		diags.Append(config.ControlPlaneKafkaHosts.ElementsAs(ctx, &cpKafkaBrokers, false)...)
+		if diags.HasError() {
+			return
+		}
 	}
 
 	cpKafkaListenerPorts := []string{}
-	diags.Append(config.ControlPlaneKafkaListenerPorts.ElementsAs(ctx, &cpKafkaListenerPorts, false)...)
-	if diags.HasError() {
+	if !(config.ControlPlaneKafkaListenerPorts.IsNull() || config.ControlPlaneKafkaListenerPorts.IsUnknown()) {
+		diags.Append(config.ControlPlaneKafkaListenerPorts.ElementsAs(ctx, &cpKafkaListenerPorts, false)...)
+		if diags.HasError() {
+			return
+		}
+	}
+	if len(cpKafkaBrokers) > 0 && len(cpKafkaListenerPorts) == 0 {
+		diags.AddError("invalid control plane kafka configuration", "cp_kafka_listener_ports is required when cp_kafka_hosts is set")
 		return
 	}
 
-	kubeClusterName, err := util.GetKubeClusterName(ctx, dp)
-	if err != nil {
-		diags.AddError("unable to get kube cluster name", err.Error())
+	kubeClusterName, dg := util.GetKubeClusterName(ctx, dp)
+	diags.Append(dg...)
+	if diags.HasError() {
 		return
 	}
 
 	rdsClusterName := fmt.Sprintf("dp-%s-%s-%s-db-0", config.InfraId.ValueString(), config.Stack.ValueString(), config.RdsResourceID.ValueString())
-	var buf bytes.Buffer
-	err = tmpl.Execute(&buf, map[string]any{
+	deploymentConfig, err := renderDeploymentConfig(map[string]any{
 		"AccountID":                            config.AccountId.ValueString(),
 		"Region":                               cfg.Region,
 		"KmsKeyId":                             config.KmsKeyId.ValueString(),
 		"DynamoDbTable":                        config.DynamoDbTableName.ValueString(),
 		"Rds":                                  pgCred,
+		"RdsIamRoleARN":                        rdsIamRoleArn,
 		"DSSecret":                             dsSecrets,
 		"KafkaBrokerList":                      strings.Join(kafkaBrokers, ","),
 		"KafkaBrokerListenerPorts":             strings.Join(kafkaListenerPorts, ","),
 		"KafkaRoleARN":                         config.KafkaRoleArn.ValueString(),
 		"KafkaRoleExternalId":                  config.KafkaRoleExternalId.ValueString(),
+		"KafkaScram":                           kafkaScram,
 		"ControlPlaneKafkaBrokerList":          strings.Join(cpKafkaBrokers, ","),
 		"ControlPlaneKafkaBrokerListenerPorts": strings.Join(cpKafkaListenerPorts, ","),
-		"ControlPlaneRegion":                   config.DsRegion.ValueString(),
+		"ControlPlaneRegion":                   dsRegion,
 		"ApiHostname":                          config.ApiHostname.ValueString(),
 		"ProductArtifactsBucket":               config.ProductArtifactsBucket.ValueString(),
 		"SerdeBucket":                          config.SerdeBucket.ValueString(),
-		"SerdeBucketRegion":                    config.DsRegion.ValueString(),
+		"SerdeBucketRegion":                    util.SerdeBucketRegion(cfg, config),
 		"WorkloadStateBucket":                  config.WorkloadStateBucket.ValueString(),
 		"O11yBucket":                           config.O11yBucket.ValueString(),
+		"O11yBucketRegion":                     util.O11yBucketRegion(cfg, config),
 		"KubeClusterName":                      kubeClusterName,
 		"KafkaClusterName":                     config.KafkaClusterName.ValueString(),
 		"RdsClusterName":                       rdsClusterName,
@@ -255,44 +350,123 @@ func UpdateDeploymentConfig(ctx context.Context, cfg aws.Config, dp awsconfig.AW
 	}
 
 	deploymentConfigSecretName := calcDeploymentConfigSecretName(config, cfg.Region)
-	if _, err := secretsmanagerClient.DescribeSecret(ctx, &secretsmanager.DescribeSecretInput{
+	if dryRun {
+		tflog.Info(ctx, "dry run: not writing deployment config", map[string]any{"secret": deploymentConfigSecretName})
+		return
+	}
+
+	tags, dg := dataplaneTags(ctx, dp, defaultTags)
+	diags.Append(dg...)
+	if diags.HasError() {
+		return
+	}
+	// the DeltaStream tags identify the secret to DeltaStream tooling and cannot be overridden
+	tags = util.MergeTags(tags, map[string]string{
+		"deltastream-io-region":  cfg.Region,
+		"deltastream-io-team":    "true",
+		"deltastream-io-is-prod": "true",
+		"deltastream-io-env":     config.Stack.ValueString(),
+		"deltastream-io-id":      config.InfraId.ValueString(),
+		"deltastream-io-name":    "dp-" + config.InfraId.ValueString(),
+		"deltastream-io-is-byoc": "true",
+	})
+	secretTags := []types.Tag{}
+	for _, k := range util.SortedTagKeys(tags) {
+		secretTags = append(secretTags, types.Tag{Key: ptr.To(k), Value: ptr.To(tags[k])})
+	}
+
+	describeOut, err := secretsmanagerClient.DescribeSecret(ctx, &secretsmanager.DescribeSecretInput{
 		SecretId: aws.String(deploymentConfigSecretName),
-	}); err != nil {
+	})
+	secretExists := err == nil
+	restore := secretExists && describeOut.DeletedDate != nil
+	if err != nil {
 		var resourceNotFoundException *types.ResourceNotFoundException
-		if errors.As(err, &resourceNotFoundException) {
-			if _, err = secretsmanagerClient.CreateSecret(ctx, &secretsmanager.CreateSecretInput{
-				Name:         ptr.To(deploymentConfigSecretName),
-				SecretString: ptr.To(buf.String()),
-				Tags: []types.Tag{
-					{Key: ptr.To("deltastream-io-region"), Value: ptr.To(cfg.Region)},
-					{Key: ptr.To("deltastream-io-team"), Value: ptr.To("true")},
-					{Key: ptr.To("deltastream-io-is-prod"), Value: ptr.To("true")},
-					{Key: ptr.To("deltastream-io-env"), Value: ptr.To(config.Stack.ValueString())},
-					{Key: ptr.To("deltastream-io-id"), Value: ptr.To(config.InfraId.ValueString())},
-					{Key: ptr.To("deltastream-io-name"), Value: ptr.To("dp-" + config.InfraId.ValueString())},
-					{Key: ptr.To("deltastream-io-is-byoc"), Value: ptr.To("true")},
-				},
-			}); err != nil {
-				diags.AddError("unable to create deployment config "+deploymentConfigSecretName, err.Error())
-				return
-			}
-		} else {
-			diags.AddError("unable to describe deployment config "+deploymentConfigSecretName, err.Error())
+		if !errors.As(err, &resourceNotFoundException) {
+			diags.AddError("unable to describe deployment config "+deploymentConfigSecretName, util.AwsErrorDetail(err, util.Remediation{Action: "secretsmanager:DescribeSecret"}))
 			return
 		}
-	} else {
-		if _, err = secretsmanagerClient.PutSecretValue(ctx, &secretsmanager.PutSecretValueInput{
-			SecretId:     ptr.To(deploymentConfigSecretName),
-			SecretString: ptr.To(buf.String()),
+
+		_, err = secretsmanagerClient.CreateSecret(ctx, &secretsmanager.CreateSecretInput{
+			Name:         ptr.To(deploymentConfigSecretName),
+			SecretString: ptr.To(string(deploymentConfig)),
+			Tags:         secretTags,
+		})
+		var resourceExistsException *types.ResourceExistsException
+		switch {
+		case err == nil:
+		case errors.As(err, &resourceExistsException):
+			// the secret was created since it was described, or the describe was served stale
+			tflog.Debug(ctx, "deployment config created concurrently, updating it", map[string]any{"secret": deploymentConfigSecretName})
+			secretExists = true
+		case isScheduledForDeletion(err):
+			// the secret was deleted without force by a prior destroy and is still within its recovery window
+			secretExists = true
+			restore = true
+		default:
+			diags.AddError("unable to create deployment config "+deploymentConfigSecretName, util.AwsErrorDetail(err, util.Remediation{Action: "secretsmanager:CreateSecret"}))
+			return
+		}
+	}
+
+	if !secretExists {
+		return
+	}
+
+	if restore {
+		tflog.Info(ctx, "restoring deployment config scheduled for deletion", map[string]any{"secret": deploymentConfigSecretName})
+		if _, err := secretsmanagerClient.RestoreSecret(ctx, &secretsmanager.RestoreSecretInput{
+			SecretId: ptr.To(deploymentConfigSecretName),
 		}); err != nil {
-			diags.AddError("unable to write deployment config "+deploymentConfigSecretName, err.Error())
+			diags.AddError("unable to restore deployment config "+deploymentConfigSecretName, util.AwsErrorDetail(err, util.Remediation{Action: "secretsmanager:RestoreSecret"}))
 			return
 		}
 	}
 
+	if _, err = secretsmanagerClient.PutSecretValue(ctx, &secretsmanager.PutSecretValueInput{
+		SecretId:     ptr.To(deploymentConfigSecretName),
+		SecretString: ptr.To(string(deploymentConfig)),
+	}); err != nil {
+		diags.AddError("unable to write deployment config "+deploymentConfigSecretName, util.AwsErrorDetail(err, util.Remediation{Action: "secretsmanager:PutSecretValue"}))
+		return
+	}
+	if _, err = secretsmanagerClient.TagResource(ctx, &secretsmanager.TagResourceInput{
+		SecretId: ptr.To(deploymentConfigSecretName),
+		Tags:     secretTags,
+	}); err != nil {
+		diags.AddError("unable to tag deployment config "+deploymentConfigSecretName, util.AwsErrorDetail(err, util.Remediation{Action: "secretsmanager:TagResource"}))
+		return
+	}
+
 	return
 }
 
+// isScheduledForDeletion reports whether a Secrets Manager request failed because the secret is pending deletion.
+func isScheduledForDeletion(err error) bool {
+	var invalidRequestException *types.InvalidRequestException
+	return errors.As(err, &invalidRequestException) && strings.Contains(invalidRequestException.ErrorMessage(), "scheduled for deletion")
+}
+
+// rdsCredentialsSecretId returns the configured RDS credentials secret, or the secret created for the RDS instance when
+// none is configured.
+func rdsCredentialsSecretId(ctx context.Context, cfg aws.Config, config awsconfig.ClusterConfiguration) string {
+	if !(config.RdsCredentialsSecretId.IsNull() || config.RdsCredentialsSecretId.IsUnknown()) {
+		tflog.Debug(ctx, "using configured rds credentials secret", map[string]any{"secret": config.RdsCredentialsSecretId.ValueString()})
+		return config.RdsCredentialsSecretId.ValueString()
+	}
+	return fmt.Sprintf("%s:secret:deltastream/%s/dp-%s/rds/%s/%s/db/credential-0", util.GetARNForService(ctx, cfg, config, "secretsmanager"), config.Stack.ValueString(), config.InfraId.ValueString(), cfg.Region, config.RdsResourceID.ValueString())
+}
+
+// stripPort removes the port from a host:port address, bracketed IPv6 addresses are returned without brackets. Hosts
+// without a port are returned unchanged.
+func stripPort(hostPort string) string {
+	host, _, err := net.SplitHostPort(hostPort)
+	if err != nil {
+		return hostPort
+	}
+	return host
+}
+
 func calcDeploymentConfigSecretName(config awsconfig.ClusterConfiguration, region string) string {
 	return fmt.Sprintf("deltastream/%s/dp/%s/aws/%s/%s/deployment-config", config.Stack.ValueString(), config.InfraId.ValueString(), region, config.EksResourceId.ValueString())
 }
@@ -0,0 +1,116 @@
+// Copyright (c) DeltaStream, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package aws
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestStripPort(t *testing.T) {
+	tests := []struct {
+		name string
+		host string
+		want string
+	}{
+		{name: "plain host", host: "db.example.com", want: "db.example.com"},
+		{name: "host and port", host: "db.example.com:5432", want: "db.example.com"},
+		{name: "ipv4 and port", host: "10.0.0.1:5432", want: "10.0.0.1"},
+		{name: "bracketed ipv6 and port", host: "[::1]:5432", want: "::1"},
+		{name: "bare ipv6", host: "fd00::1", want: "fd00::1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := stripPort(tt.host); got != tt.want {
+				t.Errorf("stripPort(%q) = %q, want %q", tt.host, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeploymentConfigTmplCw2Loki(t *testing.T) {
+	tests := []struct {
+		name        string
+		sqsURL      string
+		wantCw2Loki bool
+	}{
+		{name: "cw2loki configured", sqsURL: "https://sqs.us-east-1.amazonaws.com/123456789012/cw2loki", wantCw2Loki: true},
+		{name: "cw2loki not configured", sqsURL: "", wantCw2Loki: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rendered, err := renderDeploymentConfig(map[string]any{
+				"DSSecret":      DSSecrets{},
+				"Rds":           map[string]any{"Port": 5432},
+				"Cw2LokiSqsURL": tt.sqsURL,
+			})
+			if err != nil {
+				t.Fatalf("rendering deployment config: %v", err)
+			}
+
+			var config struct {
+				Cw2Loki *struct {
+					SqsURL string `json:"sqsURL"`
+				} `json:"cw2loki"`
+				S3 map[string]any `json:"s3"`
+			}
+			if err := json.Unmarshal(rendered, &config); err != nil {
+				t.Fatalf("rendered deployment config is not valid JSON: %v\n%s", err, rendered)
+			}
+			if got := config.Cw2Loki != nil; got != tt.wantCw2Loki {
+				t.Errorf("cw2loki block rendered = %v, want %v", got, tt.wantCw2Loki)
+			}
+			if _, got := config.S3["cw2loki"]; got != tt.wantCw2Loki {
+				t.Errorf("cw2loki bucket rendered = %v, want %v", got, tt.wantCw2Loki)
+			}
+			if tt.wantCw2Loki && config.Cw2Loki.SqsURL != tt.sqsURL {
+				t.Errorf("cw2loki sqsURL = %q, want %q", config.Cw2Loki.SqsURL, tt.sqsURL)
+			}
+		})
+	}
+}
+
+func TestDeploymentConfigTmplEscapesCredentials(t *testing.T) {
+	password := `a&b"c<d'e\f`
+	rendered, err := renderDeploymentConfig(map[string]any{
+		"DSSecret":   DSSecrets{GoogleClientSecret: password},
+		"Rds":        PostgresCredSecret{Username: "dp<admin>", Password: password, Port: 5432},
+		"KafkaScram": &KafkaScramCredSecret{Username: `kafka"user`, Password: password},
+	})
+	if err != nil {
+		t.Fatalf("rendering deployment config: %v", err)
+	}
+
+	var config struct {
+		Postgres struct {
+			Username string `json:"username"`
+			Password string `json:"password"`
+		} `json:"postgres"`
+		Kafka struct {
+			Username string `json:"username"`
+			Password string `json:"password"`
+		} `json:"kafka"`
+		GoogleOAuth struct {
+			ClientSecret string `json:"clientSecret"`
+		} `json:"googleOAuth"`
+	}
+	if err := json.Unmarshal(rendered, &config); err != nil {
+		t.Fatalf("rendered deployment config is not valid JSON: %v\n%s", err, rendered)
+	}
+	for name, got := range map[string]string{
+		"postgres password":    config.Postgres.Password,
+		"kafka password":       config.Kafka.Password,
+		"google client secret": config.GoogleOAuth.ClientSecret,
+	} {
+		if got != password {
+			t.Errorf("%s = %q, want %q", name, got, password)
+		}
+	}
+	if config.Postgres.Username != "dp<admin>" {
+		t.Errorf("postgres username = %q, want %q", config.Postgres.Username, "dp<admin>")
+	}
+	if config.Kafka.Username != `kafka"user` {
+		t.Errorf("kafka username = %q, want %q", config.Kafka.Username, `kafka"user`)
+	}
+}
@@ -15,7 +15,9 @@ import (
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/sethvargo/go-retry"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -25,13 +27,12 @@ import (
 	"github.com/deltastreaminc/terraform-provider-dataplane/internal/deltastream/aws/util"
 )
 
-var retrylimits = retry.WithMaxRetries(5, retry.NewExponential(time.Second*5))
-
-func getKustomization(ctx context.Context, kubeClient *util.RetryableClient, name string) (_ *kustomizev1.Kustomization, d diag.Diagnostics) {
+func getKustomization(ctx context.Context, kubeClient *util.RetryableClient, namespace, name string) (_ *kustomizev1.Kustomization, d diag.Diagnostics) {
 	kustomization := &kustomizev1.Kustomization{}
-	if err := retry.Do(ctx, retrylimits, func(ctx context.Context) error {
-		if err := kubeClient.Get(ctx, client.ObjectKey{Name: name, Namespace: "cluster-config"}, kustomization); err != nil {
-			if k8serrors.IsNotFound(err) {
+	if err := retry.Do(ctx, kubeClient.RetryBackoff(), func(ctx context.Context) error {
+		if err := kubeClient.Get(ctx, client.ObjectKey{Name: name, Namespace: namespace}, kustomization); err != nil {
+			// before Flux is installed the Kustomization CRD does not exist, there is no kustomization either
+			if k8serrors.IsNotFound(err) || meta.IsNoMatchError(err) {
 				kustomization = nil
 				return nil
 			}
@@ -46,8 +47,8 @@ func getKustomization(ctx context.Context, kubeClient *util.RetryableClient, nam
 	return kustomization, d
 }
 
-func deleteKustomization(ctx context.Context, kubeClient *util.RetryableClient, name string) (d diag.Diagnostics) {
-	kustomization, diags := getKustomization(ctx, kubeClient, name)
+func deleteKustomization(ctx context.Context, kubeClient *util.RetryableClient, namespace, name string) (d diag.Diagnostics) {
+	kustomization, diags := getKustomization(ctx, kubeClient, namespace, name)
 	d.Append(diags...)
 	if d.HasError() {
 		return
@@ -55,7 +56,7 @@ func deleteKustomization(ctx context.Context, kubeClient *util.RetryableClient,
 
 	if kustomization != nil {
 		tflog.Debug(ctx, "Delete "+name+" kustomization")
-		if err := retry.Do(ctx, retrylimits, func(ctx context.Context) error {
+		if err := retry.Do(ctx, kubeClient.RetryBackoff(), func(ctx context.Context) error {
 			if err := kubeClient.Delete(ctx, kustomization, &client.DeleteOptions{PropagationPolicy: ptr.To(metav1.DeletePropagationForeground)}); err != nil {
 				if k8serrors.IsNotFound(err) {
 					return nil
@@ -72,8 +73,8 @@ func deleteKustomization(ctx context.Context, kubeClient *util.RetryableClient,
 	return d
 }
 
-func suspendKustomization(ctx context.Context, kubeClient *util.RetryableClient, name string) (d diag.Diagnostics) {
-	kustomization, diags := getKustomization(ctx, kubeClient, name)
+func suspendKustomization(ctx context.Context, kubeClient *util.RetryableClient, namespace, name string) (d diag.Diagnostics) {
+	kustomization, diags := getKustomization(ctx, kubeClient, namespace, name)
 	d.Append(diags...)
 	if d.HasError() {
 		return
@@ -82,7 +83,7 @@ func suspendKustomization(ctx context.Context, kubeClient *util.RetryableClient,
 	if kustomization != nil {
 		tflog.Debug(ctx, "Suspend "+name+" kustomization")
 		kustomization.Spec.Suspend = true
-		if err := retry.Do(ctx, retrylimits, func(ctx context.Context) error {
+		if err := retry.Do(ctx, kubeClient.RetryBackoff(), func(ctx context.Context) error {
 			err := kubeClient.Update(ctx, kustomization)
 			if err != nil {
 				tflog.Debug(ctx, "failed to suspend "+name+" kustomization "+err.Error())
@@ -97,32 +98,78 @@ func suspendKustomization(ctx context.Context, kubeClient *util.RetryableClient,
 	return d
 }
 
-func cleanup(ctx context.Context, cfg aws.Config, dp awsconfig.AWSDataplane) (d diag.Diagnostics) {
-	kubeClient, err := util.GetKubeClient(ctx, cfg, dp)
-	if err != nil {
-		d.AddError("error getting kube client", err.Error())
+func resumeKustomization(ctx context.Context, kubeClient *util.RetryableClient, namespace, name string) (d diag.Diagnostics) {
+	kustomization, diags := getKustomization(ctx, kubeClient, namespace, name)
+	d.Append(diags...)
+	if d.HasError() {
 		return
 	}
 
-	d.Append(suspendKustomization(ctx, kubeClient, "istio")...)
-	if d.HasError() {
-		return
+	if kustomization != nil && kustomization.Spec.Suspend {
+		tflog.Debug(ctx, "Resume "+name+" kustomization")
+		kustomization.Spec.Suspend = false
+		if err := retry.Do(ctx, kubeClient.RetryBackoff(), func(ctx context.Context) error {
+			err := kubeClient.Update(ctx, kustomization)
+			if err != nil {
+				tflog.Debug(ctx, "failed to resume "+name+" kustomization "+err.Error())
+				return retry.RetryableError(err)
+			}
+			return nil
+		}); err != nil {
+			d.AddError("failed to resume "+name, err.Error())
+			return
+		}
 	}
+	return d
+}
 
-	d.Append(suspendKustomization(ctx, kubeClient, "istio-api-ingress")...)
-	if d.HasError() {
-		return
+func cordonNode(ctx context.Context, kubeClient *util.RetryableClient, nodeName string) error {
+	node := &corev1.Node{}
+	if err := kubeClient.Get(ctx, client.ObjectKey{Name: nodeName}, node); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to get node %s: %w", nodeName, err)
+	}
+	if node.Spec.Unschedulable {
+		return nil
 	}
 
-	d.Append(suspendKustomization(ctx, kubeClient, "istio-grafana-ingress")...)
-	if d.HasError() {
-		return
+	tflog.Debug(ctx, "cordoning node "+nodeName)
+	patch := client.MergeFrom(node.DeepCopy())
+	node.Spec.Unschedulable = true
+	if err := kubeClient.Patch(ctx, node, patch); err != nil && !k8serrors.IsNotFound(err) {
+		return fmt.Errorf("failed to cordon node %s: %w", nodeName, err)
 	}
+	return nil
+}
 
+// evictPod evicts the pod through the eviction API so that PodDisruptionBudgets are honored
+func evictPod(ctx context.Context, kubeClient *util.RetryableClient, pod corev1.Pod) error {
+	eviction := &policyv1.Eviction{
+		ObjectMeta: metav1.ObjectMeta{Name: pod.Name, Namespace: pod.Namespace},
+	}
+	if err := kubeClient.Client.SubResource("eviction").Create(ctx, &pod, eviction); err != nil && !k8serrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+func isDaemonSetPod(pod corev1.Pod) bool {
+	for _, owner := range pod.OwnerReferences {
+		if owner.Kind == "DaemonSet" {
+			return true
+		}
+	}
+	return false
+}
+
+// deleteLoadBalancerServices deletes the LoadBalancer services in the namespace to release their NLBs
+func deleteLoadBalancerServices(ctx context.Context, kubeClient *util.RetryableClient, namespace string) (d diag.Diagnostics) {
 	tflog.Debug(ctx, "get list of services in istio namespace")
 	svcs := corev1.ServiceList{}
-	if err := retry.Do(ctx, retrylimits, func(ctx context.Context) error {
-		err := kubeClient.List(ctx, &svcs, client.InNamespace("istio-system"))
+	if err := retry.Do(ctx, kubeClient.RetryBackoff(), func(ctx context.Context) error {
+		err := kubeClient.List(ctx, &svcs, client.InNamespace(namespace))
 		if err != nil {
 			tflog.Debug(ctx, "failed to get list of services in istio namespace "+err.Error())
 			return retry.RetryableError(err)
@@ -138,7 +185,7 @@ func cleanup(ctx context.Context, cfg aws.Config, dp awsconfig.AWSDataplane) (d
 		if svc.Spec.Type != corev1.ServiceTypeLoadBalancer {
 			continue
 		}
-		if err := retry.Do(ctx, retrylimits, func(ctx context.Context) error {
+		if err := retry.Do(ctx, kubeClient.RetryBackoff(), func(ctx context.Context) error {
 			err := kubeClient.Delete(ctx, &svc, &client.DeleteOptions{PropagationPolicy: ptr.To(metav1.DeletePropagationForeground)})
 			if err != nil {
 				if k8serrors.IsNotFound(err) {
@@ -153,20 +200,72 @@ func cleanup(ctx context.Context, cfg aws.Config, dp awsconfig.AWSDataplane) (d
 			return
 		}
 	}
+	return
+}
+
+func cleanup(ctx context.Context, cfg aws.Config, dp awsconfig.AWSDataplane, getKubeClient kubeClientFactory) (d diag.Diagnostics) {
+	clusterCfg, diags := dp.ClusterConfigurationData(ctx)
+	d.Append(diags...)
+	if d.HasError() {
+		return
+	}
+
+	if clusterCfg.DeletionMode.ValueString() == awsconfig.DeletionModeRelease {
+		tflog.Info(ctx, "deletion mode is release, leaving DeltaStream installed in the cluster")
+		d.Append(deleteDeploymentConfigSecret(ctx, cfg, clusterCfg)...)
+		return
+	}
+
+	if clusterCfg.ForceDestroy.ValueBool() {
+		tflog.Warn(ctx, "force destroy enabled, skipping in-cluster cleanup")
+		d.Append(deleteDeploymentConfigSecret(ctx, cfg, clusterCfg)...)
+		return
+	}
+
+	kubeClient, diags := getKubeClient(ctx, cfg, dp)
+	d.Append(diags...)
+	if d.HasError() {
+		return
+	}
+	namespaces := clusterCfg.Namespaces()
+
+	d.Append(suspendKustomization(ctx, kubeClient, namespaces.ClusterConfig, "istio")...)
+	if d.HasError() {
+		return
+	}
+
+	d.Append(suspendKustomization(ctx, kubeClient, namespaces.ClusterConfig, "istio-api-ingress")...)
+	if d.HasError() {
+		return
+	}
+
+	d.Append(suspendKustomization(ctx, kubeClient, namespaces.ClusterConfig, "istio-grafana-ingress")...)
+	if d.HasError() {
+		return
+	}
+
+	if clusterCfg.SkipLoadBalancerCleanup.ValueBool() {
+		tflog.Debug(ctx, "skipping loadbalancer service cleanup")
+	} else {
+		d.Append(deleteLoadBalancerServices(ctx, kubeClient, namespaces.IstioSystem)...)
+		if d.HasError() {
+			return
+		}
+	}
 
-	d.Append(deleteKustomization(ctx, kubeClient, "data-plane")...)
+	d.Append(deleteKustomization(ctx, kubeClient, namespaces.ClusterConfig, "data-plane")...)
 	if d.HasError() {
 		return
 	}
 
-	d.Append(suspendKustomization(ctx, kubeClient, "infra")...)
+	d.Append(suspendKustomization(ctx, kubeClient, namespaces.ClusterConfig, "infra")...)
 	if d.HasError() {
 		return
 	}
 
 	kustomizations := kustomizev1.KustomizationList{}
-	if err := retry.Do(ctx, retrylimits, func(ctx context.Context) error {
-		err := kubeClient.List(ctx, &kustomizations, client.InNamespace("cluster-config"))
+	if err := retry.Do(ctx, kubeClient.RetryBackoff(), func(ctx context.Context) error {
+		err := kubeClient.List(ctx, &kustomizations, client.InNamespace(namespaces.ClusterConfig))
 		if err != nil {
 			tflog.Debug(ctx, "failed to list kustomizations "+err.Error())
 			return retry.RetryableError(err)
@@ -182,34 +281,82 @@ func cleanup(ctx context.Context, cfg aws.Config, dp awsconfig.AWSDataplane) (d
 			continue
 		}
 
-		d.Append(deleteKustomization(ctx, kubeClient, kustomization.Name)...)
+		d.Append(deleteKustomization(ctx, kubeClient, namespaces.ClusterConfig, kustomization.Name)...)
 		if d.HasError() {
 			return
 		}
 	}
 
+	nodeClaimDrainTimeout, err := time.ParseDuration(clusterCfg.NodeClaimDrainTimeout.ValueString())
+	if err != nil {
+		d.AddError("invalid node claim drain timeout", err.Error())
+		return
+	}
+
+	podEvictionGracePeriod, err := time.ParseDuration(clusterCfg.PodEvictionGracePeriod.ValueString())
+	if err != nil {
+		d.AddError("invalid pod eviction grace period", err.Error())
+		return
+	}
+
+	// first eviction attempt per pod, pods that have not terminated after the grace period are force deleted
+	evictionStarted := map[client.ObjectKey]time.Time{}
 	nodeClaims := karpenterv1beta1.NodeClaimList{}
-	if err := retry.Do(ctx, retry.WithMaxDuration(time.Minute*20, retry.NewConstant(time.Second*10)), func(ctx context.Context) error {
-		kubeClient, err := util.GetKubeClient(ctx, cfg, dp)
-		if err != nil {
-			return retry.RetryableError(err)
+	if err := retry.Do(ctx, retry.WithMaxDuration(nodeClaimDrainTimeout, retry.WithJitterPercent(20, retry.NewConstant(time.Second*10))), func(ctx context.Context) error {
+		if err := ctx.Err(); err != nil {
+			return err
 		}
 
-		err = kubeClient.List(ctx, &nodeClaims)
+		kubeClient, diags := getKubeClient(ctx, cfg, dp)
+		if diags.HasError() {
+			return retry.RetryableError(util.DiagnosticsError(diags))
+		}
+
+		err := kubeClient.List(ctx, &nodeClaims)
 		if err != nil {
 			tflog.Debug(ctx, "failed to list node claims "+err.Error())
 			return retry.RetryableError(err)
 		}
 
 		for _, nodeClaim := range nodeClaims.Items {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if nodeClaim.Status.NodeName == "" {
+				continue
+			}
+			if err := cordonNode(ctx, kubeClient, nodeClaim.Status.NodeName); err != nil {
+				return retry.RetryableError(err)
+			}
+
 			podList := corev1.PodList{}
 			if err := kubeClient.List(ctx, &podList, client.MatchingFields{"spec.nodeName": nodeClaim.Status.NodeName}); err != nil {
 				return retry.RetryableError(fmt.Errorf("failed to list pods on node %s: %w", nodeClaim.Status.NodeName, err))
 			}
 
 			for _, pod := range podList.Items {
-				if err := kubeClient.Delete(ctx, &pod); err != nil {
-					return retry.RetryableError(fmt.Errorf("failed to delete pod %s: %w", pod.Name, err))
+				if isDaemonSetPod(pod) {
+					continue
+				}
+
+				key := client.ObjectKeyFromObject(&pod)
+				started, ok := evictionStarted[key]
+				if !ok {
+					started = time.Now()
+					evictionStarted[key] = started
+				}
+
+				if time.Since(started) > podEvictionGracePeriod {
+					tflog.Debug(ctx, "force deleting pod after eviction grace period", map[string]any{"pod": key.String(), "grace period": podEvictionGracePeriod.String()})
+					if err := kubeClient.Delete(ctx, &pod, client.GracePeriodSeconds(0)); err != nil && !k8serrors.IsNotFound(err) {
+						return retry.RetryableError(fmt.Errorf("failed to delete pod %s: %w", pod.Name, err))
+					}
+					continue
+				}
+
+				if err := evictPod(ctx, kubeClient, pod); err != nil {
+					// evictions blocked by a PodDisruptionBudget are retried on the next pass
+					tflog.Debug(ctx, "unable to evict pod", map[string]any{"pod": key.String(), "error": err.Error()})
 				}
 			}
 		}
@@ -220,22 +367,36 @@ func cleanup(ctx context.Context, cfg aws.Config, dp awsconfig.AWSDataplane) (d
 		}
 		return nil
 	}); err != nil {
-		d.AddError("failed while waiting for node claims to be cleaned up", err.Error())
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			d.AddError("node claim cleanup cancelled", ctxErr.Error())
+			return
+		}
+		d.AddError(fmt.Sprintf("failed while waiting for node claims to be cleaned up (%d remaining after %s)", len(nodeClaims.Items), nodeClaimDrainTimeout), err.Error())
 	}
 
-	// Delete cluster-config secret
-	clusterCfg, diags := dp.ClusterConfigurationData(ctx)
-	d.Append(diags...)
-	if d.HasError() {
+	d.Append(deleteDeploymentConfigSecret(ctx, cfg, clusterCfg)...)
+	return
+}
+
+// deleteDeploymentConfigSecret removes the deployment config secret unless it is configured to be retained
+func deleteDeploymentConfigSecret(ctx context.Context, cfg aws.Config, clusterCfg awsconfig.ClusterConfiguration) (d diag.Diagnostics) {
+	if clusterCfg.RetainSecretsOnDestroy.ValueBool() {
+		tflog.Debug(ctx, "Retaining cluster settings secret")
 		return
 	}
 
 	tflog.Debug(ctx, "Delete cluster settings secret")
-	secretsClient := secretsmanager.NewFromConfig(cfg)
-	if _, err := secretsClient.DeleteSecret(ctx, &secretsmanager.DeleteSecretInput{
+	deleteSecretInput := &secretsmanager.DeleteSecretInput{
 		SecretId:                   ptr.To(calcDeploymentConfigSecretName(clusterCfg, cfg.Region)),
 		ForceDeleteWithoutRecovery: ptr.To(true),
-	}); err != nil {
+	}
+	if recoveryWindow := clusterCfg.SecretDeletionRecoveryWindowDays.ValueInt64(); recoveryWindow > 0 {
+		deleteSecretInput.ForceDeleteWithoutRecovery = nil
+		deleteSecretInput.RecoveryWindowInDays = ptr.To(recoveryWindow)
+	}
+
+	secretsClient := secretsmanager.NewFromConfig(cfg)
+	if _, err := secretsClient.DeleteSecret(ctx, deleteSecretInput); err != nil {
 		d.AddError("failed to delete secret", err.Error())
 		return
 	}
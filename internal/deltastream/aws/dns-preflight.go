@@ -0,0 +1,142 @@
+// Copyright (c) DeltaStream, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package aws
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/route53"
+	r53types "github.com/aws/aws-sdk-go-v2/service/route53/types"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	awsconfig "github.com/deltastreaminc/terraform-provider-dataplane/internal/deltastream/aws/config"
+	"github.com/deltastreaminc/terraform-provider-dataplane/internal/deltastream/aws/util"
+)
+
+// checkHostnameZones warns when the records of the o11y and api hostnames can not be managed in the account: no hosted
+// zone of the account is a parent of the hostname, the public zone is not delegated to the name servers of the hosted
+// zone, or a record for the hostname already exists.
+func checkHostnameZones(ctx context.Context, client *route53.Client, clusterConfig awsconfig.ClusterConfiguration) (d diag.Diagnostics) {
+	zones := []r53types.HostedZone{}
+	paginator := route53.NewListHostedZonesPaginator(client, &route53.ListHostedZonesInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			d.AddWarning("preflight: unable to list Route53 hosted zones", util.AwsErrorDetail(err, util.Remediation{Action: "route53:ListHostedZones"}))
+			return
+		}
+		zones = append(zones, page.HostedZones...)
+	}
+	zoneNames := make([]string, 0, len(zones))
+	for _, zone := range zones {
+		zoneNames = append(zoneNames, aws.ToString(zone.Name))
+	}
+
+	hostnames := []struct{ attr, name string }{
+		{"o11y_hostname", clusterConfig.O11yHostname.ValueString()},
+		{"api_hostname", clusterConfig.ApiHostname.ValueString()},
+	}
+	for _, hostname := range hostnames {
+		i := matchHostedZone(hostname.name, zoneNames)
+		if i < 0 {
+			d.AddWarning("preflight: no Route53 hosted zone for "+hostname.name,
+				fmt.Sprintf("No hosted zone of the account is a parent of configuration.%s, so the record of the endpoint can not be managed. Create the hosted zone and delegate it from the parent domain.", hostname.attr))
+			continue
+		}
+		d.Append(checkHostnameZone(ctx, client, zones[i], hostname.name)...)
+	}
+	return
+}
+
+// checkHostnameZone warns when the hostname already has a record in the zone or when the public zone is not delegated
+// to the name servers of the hosted zone.
+func checkHostnameZone(ctx context.Context, client *route53.Client, zone r53types.HostedZone, hostname string) (d diag.Diagnostics) {
+	zoneName := strings.TrimSuffix(aws.ToString(zone.Name), ".")
+	tflog.Debug(ctx, "checking hosted zone", map[string]any{"hostname": hostname, "zone": zoneName})
+
+	recordsOut, err := client.ListResourceRecordSets(ctx, &route53.ListResourceRecordSetsInput{
+		HostedZoneId:    zone.Id,
+		StartRecordName: aws.String(hostname),
+		MaxItems:        aws.Int32(10),
+	})
+	if err != nil {
+		d.AddWarning("preflight: unable to list records of hosted zone "+zoneName, util.AwsErrorDetail(err, util.Remediation{Action: "route53:ListResourceRecordSets"}))
+	} else {
+		for _, record := range recordsOut.ResourceRecordSets {
+			if !strings.EqualFold(strings.TrimSuffix(aws.ToString(record.Name), "."), hostname) {
+				continue
+			}
+			switch record.Type {
+			case r53types.RRTypeA, r53types.RRTypeAaaa, r53types.RRTypeCname:
+				d.AddWarning("preflight: "+hostname+" already has a record",
+					fmt.Sprintf("The hosted zone %s has a %s record for %s, it collides with the record of the endpoint.", zoneName, record.Type, hostname))
+			}
+		}
+	}
+
+	// private zones are not delegated
+	if zone.Config != nil && zone.Config.PrivateZone {
+		return
+	}
+	zoneOut, err := client.GetHostedZone(ctx, &route53.GetHostedZoneInput{Id: zone.Id})
+	if err != nil {
+		d.AddWarning("preflight: unable to read hosted zone "+zoneName, util.AwsErrorDetail(err, util.Remediation{Action: "route53:GetHostedZone"}))
+		return
+	}
+	if zoneOut.DelegationSet == nil {
+		return
+	}
+	nameServers, err := net.DefaultResolver.LookupNS(ctx, zoneName)
+	if err != nil {
+		d.AddWarning("preflight: hosted zone "+zoneName+" is not delegated",
+			fmt.Sprintf("Looking up the name servers of %s failed: %s. Delegate the zone to %s in the parent domain.", zoneName, err.Error(), strings.Join(zoneOut.DelegationSet.NameServers, ", ")))
+		return
+	}
+	resolved := make([]string, 0, len(nameServers))
+	for _, ns := range nameServers {
+		resolved = append(resolved, ns.Host)
+	}
+	if !nameServersOverlap(resolved, zoneOut.DelegationSet.NameServers) {
+		d.AddWarning("preflight: hosted zone "+zoneName+" is not delegated",
+			fmt.Sprintf("%s is served by %s, the hosted zone uses %s. Delegate the zone to the name servers of the hosted zone in the parent domain.", zoneName, strings.Join(resolved, ", "), strings.Join(zoneOut.DelegationSet.NameServers, ", ")))
+	}
+	return
+}
+
+// matchHostedZone returns the index of the most specific zone that is a parent of the hostname, -1 when there is none.
+func matchHostedZone(hostname string, zoneNames []string) int {
+	hostname = normalizeDnsName(hostname)
+	match := -1
+	for i, zoneName := range zoneNames {
+		zoneName = normalizeDnsName(zoneName)
+		if hostname != zoneName && !strings.HasSuffix(hostname, "."+zoneName) {
+			continue
+		}
+		if match < 0 || len(zoneName) > len(normalizeDnsName(zoneNames[match])) {
+			match = i
+		}
+	}
+	return match
+}
+
+// nameServersOverlap reports whether the two lists of name servers have a name server in common.
+func nameServersOverlap(a, b []string) bool {
+	for _, x := range a {
+		for _, y := range b {
+			if normalizeDnsName(x) == normalizeDnsName(y) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func normalizeDnsName(name string) string {
+	return strings.ToLower(strings.TrimSuffix(name, "."))
+}
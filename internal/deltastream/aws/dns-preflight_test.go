@@ -0,0 +1,38 @@
+// Copyright (c) DeltaStream, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package aws
+
+import "testing"
+
+func TestMatchHostedZone(t *testing.T) {
+	zoneNames := []string{"example.com.", "dp.example.com.", "other.com.", "ample.com."}
+	tests := []struct {
+		hostname string
+		want     int
+	}{
+		{hostname: "api.example.com", want: 0},
+		{hostname: "api.dp.example.com", want: 1},
+		{hostname: "API.DP.Example.com", want: 1},
+		{hostname: "dp.example.com", want: 1},
+		{hostname: "api.sample.com", want: -1},
+		{hostname: "api.example.org", want: -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.hostname, func(t *testing.T) {
+			if got := matchHostedZone(tt.hostname, zoneNames); got != tt.want {
+				t.Errorf("matchHostedZone(%q) = %d, want %d", tt.hostname, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNameServersOverlap(t *testing.T) {
+	hostedZone := []string{"ns-1.awsdns-01.org", "ns-2.awsdns-02.com"}
+	if !nameServersOverlap([]string{"NS-2.awsdns-02.com."}, hostedZone) {
+		t.Error("nameServersOverlap() = false, want true for a common name server")
+	}
+	if nameServersOverlap([]string{"ns1.registrar.net."}, hostedZone) {
+		t.Error("nameServersOverlap() = true, want false for different name servers")
+	}
+}
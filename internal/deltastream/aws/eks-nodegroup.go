@@ -5,33 +5,92 @@ package aws
 
 import (
 	"context"
+	"fmt"
 	"net/url"
 	"path/filepath"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	"github.com/aws/aws-sdk-go-v2/service/eks"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	karpenterv1beta1 "sigs.k8s.io/karpenter/pkg/apis/v1beta1"
 
 	awsconfig "github.com/deltastreaminc/terraform-provider-dataplane/internal/deltastream/aws/config"
 	"github.com/deltastreaminc/terraform-provider-dataplane/internal/deltastream/aws/util"
 )
 
-func restartNodes(ctx context.Context, dp awsconfig.AWSDataplane, kubeClient *util.RetryableClient) (d diag.Diagnostics) {
-	cfg, diags := util.GetAwsConfig(ctx, dp)
+// checkNodeProvisioning verifies that the cluster has nodes, or a way to provision them, before installing into it.
+// Without either the later wait for nodes to become ready can only time out.
+func checkNodeProvisioning(ctx context.Context, cfg aws.Config, dp awsconfig.AWSDataplane, getKubeClient kubeClientFactory) (d diag.Diagnostics) {
+	clusterConfig, diags := dp.ClusterConfigurationData(ctx)
 	d.Append(diags...)
 	if d.HasError() {
 		return
 	}
 
-	clusterName, err := util.GetKubeClusterName(ctx, dp)
+	clusterName, diags := util.GetKubeClusterName(ctx, dp)
+	d.Append(diags...)
+	if d.HasError() {
+		return
+	}
+
+	kubeClient, diags := getKubeClient(ctx, cfg, dp)
+	d.Append(diags...)
+	if d.HasError() {
+		return
+	}
+
+	nodes := corev1.NodeList{}
+	if err := kubeClient.List(ctx, &nodes); err != nil {
+		d.AddError("preflight: error listing nodes", err.Error())
+		return
+	}
+	if len(nodes.Items) > 0 {
+		return
+	}
+
+	nodegroupsOutput, err := eks.NewFromConfig(cfg).ListNodegroups(ctx, &eks.ListNodegroupsInput{ClusterName: aws.String(clusterName)})
 	if err != nil {
-		d.AddError("error getting cluster name", err.Error())
+		d.AddError("preflight: error listing nodegroups", util.AwsErrorDetail(err, util.Remediation{Action: "eks:ListNodegroups"}))
+		return
+	}
+	if len(nodegroupsOutput.Nodegroups) > 0 {
+		tflog.Debug(ctx, "cluster has no nodes yet, nodegroups will provision them", map[string]any{"nodegroups": nodegroupsOutput.Nodegroups})
+		return
+	}
+
+	// the Karpenter CRDs are not installed until Karpenter is bootstrapped, skip the retrying client
+	nodePools := karpenterv1beta1.NodePoolList{}
+	if err := kubeClient.Client.List(ctx, &nodePools); err != nil && !meta.IsNoMatchError(err) {
+		d.AddError("preflight: error listing Karpenter node pools", err.Error())
+		return
+	}
+	karpenterNodeRole := clusterConfig.KarpenterNodeRoleName.ValueString()
+	if len(nodePools.Items) > 0 && karpenterNodeRole != "" {
+		if _, err := iam.NewFromConfig(cfg).GetRole(ctx, &iam.GetRoleInput{RoleName: aws.String(karpenterNodeRole)}); err != nil {
+			d.AddError("preflight: Karpenter node role "+karpenterNodeRole+" is not accessible", util.AwsErrorDetail(err, util.Remediation{Action: "iam:GetRole", Attribute: "karpenter_node_role_name"}))
+			return
+		}
+		tflog.Debug(ctx, "cluster has no nodes yet, Karpenter will provision them", map[string]any{"node pools": len(nodePools.Items)})
+		return
+	}
+
+	d.AddError("preflight: cluster has no nodes and no Karpenter provisioner",
+		fmt.Sprintf("EKS cluster %s has no nodes, no managed nodegroups and no Karpenter node pools. Create a managed nodegroup or bootstrap Karpenter before installing the dataplane.", clusterName))
+	return
+}
+
+func restartNodes(ctx context.Context, cfg aws.Config, dp awsconfig.AWSDataplane, kubeClient *util.RetryableClient) (d diag.Diagnostics) {
+	clusterName, diags := util.GetKubeClusterName(ctx, dp)
+	d.Append(diags...)
+	if d.HasError() {
 		return
 	}
 
@@ -43,51 +102,77 @@ func restartNodes(ctx context.Context, dp awsconfig.AWSDataplane, kubeClient *ut
 		ClusterName: &clusterName,
 	})
 	if err != nil {
-		d.AddError("error listing nodegroups", err.Error())
+		d.AddError("error listing nodegroups", util.AwsErrorDetail(err, util.Remediation{Action: "eks:ListNodegroups"}))
 		return
 	}
 	tflog.Debug(ctx, "found node groups", map[string]any{"nodegroups": nodegroupsOutput.Nodegroups})
 
+	instanceIDs := []string{}
 	for _, nodegroupName := range nodegroupsOutput.Nodegroups {
+		if err := ctx.Err(); err != nil {
+			d.AddError("node restart cancelled", err.Error())
+			return
+		}
+
 		nodes := corev1.NodeList{}
 		if err = kubeClient.List(ctx, &nodes, client.MatchingLabels{"eks.amazonaws.com/nodegroup": nodegroupName}); err != nil {
 			d.AddError("error listing nodes in nodegroup", err.Error())
 			return
 		}
 
-		instanceIDs := []string{}
+		nodegroupInstanceIDs := []string{}
 		for _, node := range nodes.Items {
 			u, err := url.Parse(node.Spec.ProviderID)
 			if err != nil {
 				d.AddError("error parsing node provider ID: "+node.Spec.ProviderID, err.Error())
 				return
 			}
-			instanceIDs = append(instanceIDs, filepath.Base(u.Path))
+			nodegroupInstanceIDs = append(nodegroupInstanceIDs, filepath.Base(u.Path))
 		}
-		tflog.Debug(ctx, "found instances in node group", map[string]any{"nodegroup": nodegroupName, "instances": instanceIDs})
+		tflog.Debug(ctx, "found instances in node group", map[string]any{"nodegroup": nodegroupName, "instances": nodegroupInstanceIDs})
+		instanceIDs = append(instanceIDs, nodegroupInstanceIDs...)
+	}
 
-		_, err := ec2Client.RebootInstances(ctx, &ec2.RebootInstancesInput{
-			InstanceIds: instanceIDs,
-		})
-		if err != nil {
-			d.AddError("error rebooting instances", err.Error())
-			return
-		}
-		tflog.Debug(ctx, "rebooted instances", map[string]any{"nodegroup": nodegroupName, "instances": instanceIDs})
+	if len(instanceIDs) == 0 {
+		tflog.Debug(ctx, "no instances to reboot")
+		return
 	}
+
+	// reboot all nodegroups at once, nodes are only expected to become ready again once cilium has been installed
+	// and installCilium waits for all of them together
+	_, err = ec2Client.RebootInstances(ctx, &ec2.RebootInstancesInput{
+		InstanceIds: instanceIDs,
+	})
+	if err != nil {
+		d.AddError("error rebooting instances", util.AwsErrorDetail(err, util.Remediation{Action: "ec2:RebootInstances"}))
+		return
+	}
+	tflog.Debug(ctx, "rebooted instances", map[string]any{"nodegroups": nodegroupsOutput.Nodegroups, "instances": instanceIDs})
 	return
 }
 
-func deleteAwsNode(ctx context.Context, cfg aws.Config, dp awsconfig.AWSDataplane) (d diag.Diagnostics) {
-	kubeClient, err := util.GetKubeClient(ctx, cfg, dp)
-	if err != nil {
-		d.AddError("error getting kube client", err.Error())
+func deleteAwsNode(ctx context.Context, cfg aws.Config, dp awsconfig.AWSDataplane, getKubeClient kubeClientFactory) (d diag.Diagnostics) {
+	clusterConfig, diags := dp.ClusterConfigurationData(ctx)
+	d.Append(diags...)
+	if d.HasError() {
+		return
+	}
+	// cilium is chained to the AWS VPC CNI, which keeps managing pod networking
+	if clusterConfig.NetworkMode.ValueString() == awsconfig.NetworkModeAwsCniChaining {
+		tflog.Debug(ctx, "network mode is aws-cni-chaining, keeping aws-node")
+		return
+	}
+	kubeSystemNamespace := clusterConfig.Namespaces().KubeSystem
+
+	kubeClient, diags := getKubeClient(ctx, cfg, dp)
+	d.Append(diags...)
+	if d.HasError() {
 		return
 	}
 
 	nodeRequiresRestart := false
 	awsNodeDS := &appsv1.DaemonSet{}
-	if err := kubeClient.Get(ctx, client.ObjectKey{Namespace: "kube-system", Name: "aws-node"}, awsNodeDS); err != nil {
+	if err := kubeClient.Get(ctx, client.ObjectKey{Namespace: kubeSystemNamespace, Name: "aws-node"}, awsNodeDS); err != nil {
 		if !k8serrors.IsNotFound(err) {
 			d.AddError("error getting aws-node DaemonSet", err.Error())
 			return
@@ -104,7 +189,7 @@ func deleteAwsNode(ctx context.Context, cfg aws.Config, dp awsconfig.AWSDataplan
 		}
 	}
 	awsNodeSA := &corev1.ServiceAccount{}
-	if err := kubeClient.Get(ctx, client.ObjectKey{Namespace: "kube-system", Name: "aws-node"}, awsNodeSA); err != nil {
+	if err := kubeClient.Get(ctx, client.ObjectKey{Namespace: kubeSystemNamespace, Name: "aws-node"}, awsNodeSA); err != nil {
 		if !k8serrors.IsNotFound(err) {
 			d.AddError("error getting aws-node DaemonSet", err.Error())
 			return
@@ -122,7 +207,7 @@ func deleteAwsNode(ctx context.Context, cfg aws.Config, dp awsconfig.AWSDataplan
 	}
 
 	if nodeRequiresRestart {
-		if diags := restartNodes(ctx, dp, kubeClient); diags.HasError() {
+		if diags := restartNodes(ctx, cfg, dp, kubeClient); diags.HasError() {
 			d.Append(diags...)
 			return
 		}
@@ -0,0 +1,62 @@
+// Copyright (c) DeltaStream, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package aws
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+
+	"github.com/deltastreaminc/terraform-provider-dataplane/internal/deltastream/aws/util"
+)
+
+var _ function.Function = &ClusterNameFunction{}
+
+func NewClusterNameFunction() function.Function {
+	return &ClusterNameFunction{}
+}
+
+type ClusterNameFunction struct{}
+
+func (f *ClusterNameFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "cluster_name"
+}
+
+func (f *ClusterNameFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Compute the EKS cluster name",
+		Description: "Returns the name of the EKS cluster hosting the DeltaStream dataplane.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "infra_id",
+				Description: "The infra ID of the DeltaStream dataplane (provided by DeltaStream).",
+			},
+			function.StringParameter{
+				Name:        "stack",
+				Description: "The type of DeltaStream dataplane.",
+			},
+			function.StringParameter{
+				Name:        "resource_id",
+				Description: "The resource ID of the DeltaStream dataplane (provided by DeltaStream).",
+			},
+			function.Int64Parameter{
+				Name:        "index",
+				Description: "The index of the cluster (provided by DeltaStream).",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *ClusterNameFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var infraID, stack, resourceID string
+	var index int64
+
+	resp.Diagnostics.Append(req.Arguments.Get(ctx, &infraID, &stack, &resourceID, &index)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.Result.Set(ctx, util.KubeClusterName(infraID, stack, resourceID, index))...)
+}
@@ -0,0 +1,43 @@
+// Copyright (c) DeltaStream, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package helm
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"helm.sh/helm/v3/pkg/cli"
+	"helm.sh/helm/v3/pkg/getter"
+	"helm.sh/helm/v3/pkg/repo"
+)
+
+func DownloadChart(ctx context.Context, repoURL string, chartName string, chartVersion string) (io.Reader, error) {
+	providers := getter.All(cli.New())
+
+	tflog.Debug(ctx, "looking up chart", map[string]any{"repository": repoURL, "chart": chartName, "version": chartVersion})
+	chartURL, err := repo.FindChartInRepoURL(repoURL, chartName, chartVersion, "", "", "", providers)
+	if err != nil {
+		return nil, fmt.Errorf("unable to find chart %s-%s in %s: %w", chartName, chartVersion, repoURL, err)
+	}
+
+	u, err := url.Parse(chartURL)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse chart url %s: %w", chartURL, err)
+	}
+
+	g, err := providers.ByScheme(u.Scheme)
+	if err != nil {
+		return nil, fmt.Errorf("unable to download chart %s: %w", chartURL, err)
+	}
+
+	tflog.Debug(ctx, "downloading chart", map[string]any{"url": chartURL})
+	chart, err := g.Get(chartURL)
+	if err != nil {
+		return nil, fmt.Errorf("unable to download chart %s: %w", chartURL, err)
+	}
+	return chart, nil
+}
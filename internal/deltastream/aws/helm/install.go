@@ -14,11 +14,16 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart"
 	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/releaseutil"
 	"sigs.k8s.io/yaml"
 )
 
-func InstallRelease(ctx context.Context, kubeconfig []byte, namespace string, releaseName string, chartTarball io.Reader, values []byte, installOnly bool) error {
+// InstallRelease installs or upgrades a release atomically, a failed install or upgrade is rolled back. An existing
+// release is upgraded when the chart version or the values changed, unless installOnly is set. On failure the returned
+// error includes the release status and recent history.
+func InstallRelease(ctx context.Context, kubeconfig []byte, namespace string, releaseName string, chartTarball io.Reader, values []byte, installOnly bool, timeout time.Duration) error {
 	clientGetter := NewRESTClientGetter(namespace, kubeconfig)
 
 	actionConfig := &action.Configuration{}
@@ -58,7 +63,7 @@ func InstallRelease(ctx context.Context, kubeconfig []byte, namespace string, re
 			return nil
 		}
 
-		if release.Info.Description != valueHash {
+		if release.Info.Description != valueHash || releaseChartVersion(release.Chart) != chart.Metadata.Version {
 			upgradeAction := action.NewUpgrade(actionConfig)
 			upgradeAction.Wait = true
 			upgradeAction.Namespace = namespace
@@ -67,10 +72,11 @@ func InstallRelease(ctx context.Context, kubeconfig []byte, namespace string, re
 			upgradeAction.Atomic = true
 			upgradeAction.Recreate = true
 			upgradeAction.EnableDNS = false
+			upgradeAction.Timeout = timeout
 
 			tflog.Debug(ctx, "upgrading release", map[string]any{"release": releaseName, "namespace": namespace})
 			if _, err = upgradeAction.RunWithContext(ctx, releaseName, chart, valuesMap); err != nil {
-				return fmt.Errorf("unable to upgrade release: %w", err)
+				return fmt.Errorf("unable to upgrade release: %w%s", err, releaseFailureDetail(actionConfig, releaseName))
 			}
 			return nil
 		} else {
@@ -87,12 +93,52 @@ func InstallRelease(ctx context.Context, kubeconfig []byte, namespace string, re
 	installAction.Replace = true
 	installAction.Description = valueHash
 	installAction.EnableDNS = false
-	installAction.Timeout = time.Minute * 10
+	installAction.Atomic = true
+	installAction.Timeout = timeout
 
-	tflog.Debug(ctx, "installing release", map[string]any{"release": releaseName, "namespace": namespace})
+	tflog.Debug(ctx, "installing release", map[string]any{"release": releaseName, "namespace": namespace, "timeout": timeout.String()})
 	_, err = installAction.RunWithContext(ctx, chart, valuesMap)
 	if err != nil {
-		return fmt.Errorf("unable to install release: %w", err)
+		return fmt.Errorf("unable to install release: %w%s", err, releaseFailureDetail(actionConfig, releaseName))
 	}
 	return nil
 }
+
+// releaseChartVersion returns the version of the chart of an installed release.
+func releaseChartVersion(c *chart.Chart) string {
+	if c == nil || c.Metadata == nil {
+		return ""
+	}
+	return c.Metadata.Version
+}
+
+const releaseHistoryMax = 5
+
+// releaseFailureDetail describes the current status and recent history of a release for inclusion in error messages.
+// Lookup failures are ignored, an atomic install that was rolled back leaves no release behind.
+func releaseFailureDetail(actionConfig *action.Configuration, releaseName string) string {
+	var sb strings.Builder
+
+	if rel, err := action.NewStatus(actionConfig).Run(releaseName); err == nil && rel.Info != nil {
+		fmt.Fprintf(&sb, "\n\nrelease status: %s", rel.Info.Status)
+	}
+
+	historyAction := action.NewHistory(actionConfig)
+	historyAction.Max = releaseHistoryMax
+	history, err := historyAction.Run(releaseName)
+	if err != nil || len(history) == 0 {
+		return sb.String()
+	}
+	releaseutil.SortByRevision(history)
+	if len(history) > releaseHistoryMax {
+		history = history[len(history)-releaseHistoryMax:]
+	}
+	sb.WriteString("\n\nrelease history:")
+	for _, rel := range history {
+		if rel.Info == nil {
+			continue
+		}
+		fmt.Fprintf(&sb, "\n  revision %d: %s (%s)", rel.Version, rel.Info.Status, rel.Info.Description)
+	}
+	return sb.String()
+}
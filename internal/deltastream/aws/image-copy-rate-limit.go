@@ -0,0 +1,68 @@
+// Copyright (c) DeltaStream, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package aws
+
+import (
+	"context"
+	"io"
+	"math"
+
+	"github.com/containers/image/v5/types"
+	"golang.org/x/time/rate"
+)
+
+// newCopyRateLimiter returns a limiter shared by all image copies allowing bytesPerSecond, or nil when unlimited.
+func newCopyRateLimiter(bytesPerSecond int64) *rate.Limiter {
+	if bytesPerSecond <= 0 {
+		return nil
+	}
+	burst := int(min(bytesPerSecond, math.MaxInt32))
+	return rate.NewLimiter(rate.Limit(bytesPerSecond), burst)
+}
+
+// rateLimitedReference reads the blobs of the wrapped image through the limiter.
+type rateLimitedReference struct {
+	types.ImageReference
+	limiter *rate.Limiter
+}
+
+func (r rateLimitedReference) NewImageSource(ctx context.Context, sys *types.SystemContext) (types.ImageSource, error) {
+	src, err := r.ImageReference.NewImageSource(ctx, sys)
+	if err != nil {
+		return nil, err
+	}
+	return rateLimitedSource{ImageSource: src, limiter: r.limiter}, nil
+}
+
+type rateLimitedSource struct {
+	types.ImageSource
+	limiter *rate.Limiter
+}
+
+func (s rateLimitedSource) GetBlob(ctx context.Context, info types.BlobInfo, cache types.BlobInfoCache) (io.ReadCloser, int64, error) {
+	rc, size, err := s.ImageSource.GetBlob(ctx, info, cache)
+	if err != nil {
+		return nil, 0, err
+	}
+	return &rateLimitedReader{ReadCloser: rc, ctx: ctx, limiter: s.limiter}, size, nil
+}
+
+type rateLimitedReader struct {
+	io.ReadCloser
+	ctx     context.Context
+	limiter *rate.Limiter
+}
+
+func (r *rateLimitedReader) Read(p []byte) (int, error) {
+	if len(p) > r.limiter.Burst() {
+		p = p[:r.limiter.Burst()]
+	}
+	n, err := r.ReadCloser.Read(p)
+	if n > 0 {
+		if waitErr := r.limiter.WaitN(r.ctx, n); waitErr != nil {
+			return n, waitErr
+		}
+	}
+	return n, err
+}
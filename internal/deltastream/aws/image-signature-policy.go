@@ -0,0 +1,63 @@
+// Copyright (c) DeltaStream, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package aws
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/containers/image/v5/signature"
+	"github.com/containers/image/v5/types"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+
+	awsconfig "github.com/deltastreaminc/terraform-provider-dataplane/internal/deltastream/aws/config"
+)
+
+// sigstoreRegistriesConfig makes the docker transport read and write sigstore signatures stored as attachments of the
+// images, the way cosign stores them.
+const sigstoreRegistriesConfig = "default-docker:\n  use-sigstore-attachments: true\n"
+
+// imageSignaturePolicy returns the signature policy for copying images from sourceRegistry. Unless signatures are
+// verified every image is accepted. Verified images must carry a sigstore signature made with the configured public key
+// for the same repository in signedRegistry, which differs from sourceRegistry when already verified images are
+// replicated. When verifying, the system contexts are configured to read and write the signatures and cleanup removes
+// that configuration once the copies are done.
+func imageSignaturePolicy(clusterConfig awsconfig.ClusterConfiguration, sourceRegistry, signedRegistry string, sysCtxs ...*types.SystemContext) (policy *signature.Policy, cleanup func(), d diag.Diagnostics) {
+	cleanup = func() {}
+	if clusterConfig.ImageSignaturePolicy.ValueString() != awsconfig.ImageSignaturePolicyVerify {
+		return &signature.Policy{Default: []signature.PolicyRequirement{signature.NewPRInsecureAcceptAnything()}}, cleanup, d
+	}
+
+	var identity signature.PolicyReferenceMatch = signature.NewPRMMatchRepoDigestOrExact()
+	if sourceRegistry != signedRegistry {
+		remapped, err := signature.NewPRMRemapIdentity(sourceRegistry, signedRegistry)
+		if err != nil {
+			d.AddError("error creating image signature policy", err.Error())
+			return
+		}
+		identity = remapped
+	}
+	requirement, err := signature.NewPRSigstoreSignedKeyData([]byte(clusterConfig.ImageSignaturePublicKey.ValueString()), identity)
+	if err != nil {
+		d.AddError("invalid image signature public key", err.Error())
+		return
+	}
+
+	registriesDir, err := os.MkdirTemp("", "registries.d")
+	if err != nil {
+		d.AddError("error configuring image signature verification", err.Error())
+		return
+	}
+	if err := os.WriteFile(filepath.Join(registriesDir, "sigstore.yaml"), []byte(sigstoreRegistriesConfig), 0o600); err != nil {
+		os.RemoveAll(registriesDir)
+		d.AddError("error configuring image signature verification", err.Error())
+		return
+	}
+	for _, sysCtx := range sysCtxs {
+		sysCtx.RegistriesDirPath = registriesDir
+	}
+
+	cleanup = func() { os.RemoveAll(registriesDir) }
+	return &signature.Policy{Default: []signature.PolicyRequirement{requirement}}, cleanup, d
+}
@@ -0,0 +1,166 @@
+// Copyright (c) DeltaStream, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package aws
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/eventbridge"
+	ebtypes "github.com/aws/aws-sdk-go-v2/service/eventbridge/types"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	sqstypes "github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	awsconfig "github.com/deltastreaminc/terraform-provider-dataplane/internal/deltastream/aws/config"
+	"github.com/deltastreaminc/terraform-provider-dataplane/internal/deltastream/aws/util"
+)
+
+// interruptionEventRules are the EventBridge rules forwarding the events handled by Karpenter to the interruption
+// queue. Rule names are the queue name followed by the suffix.
+var interruptionEventRules = []struct{ suffix, pattern string }{
+	{"scheduled-change", `{"source":["aws.health"],"detail-type":["AWS Health Event"]}`},
+	{"spot-interruption", `{"source":["aws.ec2"],"detail-type":["EC2 Spot Instance Interruption Warning"]}`},
+	{"rebalance", `{"source":["aws.ec2"],"detail-type":["EC2 Instance Rebalance Recommendation"]}`},
+	{"instance-state-change", `{"source":["aws.ec2"],"detail-type":["EC2 Instance State-change Notification"]}`},
+}
+
+// interruptionQueueTargetId identifies the interruption queue among the targets of the rules.
+const interruptionQueueTargetId = "InterruptionQueueTarget"
+
+// ensureInterruptionQueue creates the interruption queue when it does not exist and points the interruption event
+// rules at it, when the queue is managed by the provider. The queue policy and the rules are updated every time so
+// that they are restored if changed outside of the provider.
+func ensureInterruptionQueue(ctx context.Context, cfg aws.Config, dp awsconfig.AWSDataplane, defaultTags map[string]string) (d diag.Diagnostics) {
+	clusterConfig, diags := dp.ClusterConfigurationData(ctx)
+	d.Append(diags...)
+	if d.HasError() {
+		return
+	}
+	if !clusterConfig.ManageInterruptionQueue.ValueBool() {
+		return
+	}
+
+	tags, diags := dataplaneTags(ctx, dp, defaultTags)
+	d.Append(diags...)
+	if d.HasError() {
+		return
+	}
+
+	queueName := clusterConfig.InterruptionQueueName.ValueString()
+	sqsClient := sqs.NewFromConfig(cfg)
+	queueUrl, err := interruptionQueueUrl(ctx, sqsClient, queueName, tags)
+	if err != nil {
+		d.AddError("error creating interruption queue "+queueName, util.AwsErrorDetail(err, util.Remediation{Action: "sqs:CreateQueue", Attribute: "interruption_queue_name"}))
+		return
+	}
+
+	attrsOut, err := sqsClient.GetQueueAttributes(ctx, &sqs.GetQueueAttributesInput{
+		QueueUrl:       aws.String(queueUrl),
+		AttributeNames: []sqstypes.QueueAttributeName{sqstypes.QueueAttributeNameQueueArn},
+	})
+	if err != nil {
+		d.AddError("error reading interruption queue "+queueName, util.AwsErrorDetail(err, util.Remediation{Action: "sqs:GetQueueAttributes"}))
+		return
+	}
+	queueArn := attrsOut.Attributes[string(sqstypes.QueueAttributeNameQueueArn)]
+
+	policy, err := interruptionQueuePolicy(queueArn)
+	if err != nil {
+		d.AddError("error creating interruption queue policy", err.Error())
+		return
+	}
+	if _, err := sqsClient.SetQueueAttributes(ctx, &sqs.SetQueueAttributesInput{
+		QueueUrl:   aws.String(queueUrl),
+		Attributes: map[string]string{string(sqstypes.QueueAttributeNamePolicy): policy},
+	}); err != nil {
+		d.AddError("error setting interruption queue policy", util.AwsErrorDetail(err, util.Remediation{Action: "sqs:SetQueueAttributes"}))
+		return
+	}
+
+	ebTags := []ebtypes.Tag{}
+	for _, k := range util.SortedTagKeys(tags) {
+		ebTags = append(ebTags, ebtypes.Tag{Key: aws.String(k), Value: aws.String(tags[k])})
+	}
+	ebClient := eventbridge.NewFromConfig(cfg)
+	for _, rule := range interruptionEventRules {
+		ruleName := interruptionRuleName(queueName, rule.suffix)
+		tflog.Debug(ctx, "configuring interruption event rule", map[string]any{"rule": ruleName})
+		if _, err := ebClient.PutRule(ctx, &eventbridge.PutRuleInput{
+			Name:         aws.String(ruleName),
+			EventPattern: aws.String(rule.pattern),
+			State:        ebtypes.RuleStateEnabled,
+			Tags:         ebTags,
+		}); err != nil {
+			d.AddError("error creating interruption event rule "+ruleName, util.AwsErrorDetail(err, util.Remediation{Action: "events:PutRule"}))
+			return
+		}
+		targetsOut, err := ebClient.PutTargets(ctx, &eventbridge.PutTargetsInput{
+			Rule:    aws.String(ruleName),
+			Targets: []ebtypes.Target{{Id: aws.String(interruptionQueueTargetId), Arn: aws.String(queueArn)}},
+		})
+		if err != nil {
+			d.AddError("error setting target of interruption event rule "+ruleName, util.AwsErrorDetail(err, util.Remediation{Action: "events:PutTargets"}))
+			return
+		}
+		for _, failed := range targetsOut.FailedEntries {
+			d.AddError("error setting target of interruption event rule "+ruleName, fmt.Sprintf("%s: %s", aws.ToString(failed.ErrorCode), aws.ToString(failed.ErrorMessage)))
+		}
+	}
+	return
+}
+
+// interruptionQueueUrl returns the URL of the interruption queue, creating the queue when it does not exist.
+func interruptionQueueUrl(ctx context.Context, client *sqs.Client, queueName string, tags map[string]string) (string, error) {
+	urlOut, err := client.GetQueueUrl(ctx, &sqs.GetQueueUrlInput{QueueName: aws.String(queueName)})
+	if err == nil {
+		return aws.ToString(urlOut.QueueUrl), nil
+	}
+	var notFound *sqstypes.QueueDoesNotExist
+	if !errors.As(err, &notFound) {
+		return "", err
+	}
+
+	tflog.Info(ctx, "creating interruption queue", map[string]any{"queue": queueName})
+	createOut, err := client.CreateQueue(ctx, &sqs.CreateQueueInput{
+		QueueName: aws.String(queueName),
+		Attributes: map[string]string{
+			string(sqstypes.QueueAttributeNameMessageRetentionPeriod): "300",
+			string(sqstypes.QueueAttributeNameSqsManagedSseEnabled):   "true",
+		},
+		Tags: tags,
+	})
+	if err != nil {
+		return "", err
+	}
+	return aws.ToString(createOut.QueueUrl), nil
+}
+
+// interruptionQueuePolicy allows EventBridge and SQS to deliver events to the queue.
+func interruptionQueuePolicy(queueArn string) (string, error) {
+	policy := map[string]any{
+		"Version": "2012-10-17",
+		"Statement": []map[string]any{{
+			"Effect":    "Allow",
+			"Principal": map[string]any{"Service": []string{"events.amazonaws.com", "sqs.amazonaws.com"}},
+			"Action":    "sqs:SendMessage",
+			"Resource":  queueArn,
+		}},
+	}
+	b, err := json.Marshal(policy)
+	return string(b), err
+}
+
+// interruptionRuleName returns the name of an interruption event rule, truncating the queue name to fit the 64
+// character limit of rule names.
+func interruptionRuleName(queueName, suffix string) string {
+	if maxLen := 64 - len(suffix) - 1; len(queueName) > maxLen {
+		queueName = queueName[:maxLen]
+	}
+	return queueName + "-" + suffix
+}
@@ -0,0 +1,32 @@
+// Copyright (c) DeltaStream, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package aws
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestInterruptionRuleName(t *testing.T) {
+	tests := []struct {
+		name      string
+		queueName string
+		suffix    string
+		want      string
+	}{
+		{name: "short", queueName: "dp-interruption", suffix: "rebalance", want: "dp-interruption-rebalance"},
+		{name: "truncated", queueName: strings.Repeat("q", 80), suffix: "instance-state-change", want: strings.Repeat("q", 42) + "-instance-state-change"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := interruptionRuleName(tt.queueName, tt.suffix)
+			if got != tt.want {
+				t.Errorf("interruptionRuleName() = %q, want %q", got, tt.want)
+			}
+			if len(got) > 64 {
+				t.Errorf("interruptionRuleName() is %d characters, want at most 64", len(got))
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright (c) DeltaStream, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package aws
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	awsconfig "github.com/deltastreaminc/terraform-provider-dataplane/internal/deltastream/aws/config"
+	"github.com/deltastreaminc/terraform-provider-dataplane/internal/deltastream/aws/util"
+)
+
+// phaseLogger brackets the phases of an operation with info level log entries carrying the elapsed time, so that a
+// TF_LOG=info run gives a timeline of where time went and which phase failed.
+type phaseLogger struct {
+	operation   string
+	clusterName string
+	start       time.Time
+	phase       string
+	phaseStart  time.Time
+}
+
+func newPhaseLogger(ctx context.Context, operation string, dp awsconfig.AWSDataplane) *phaseLogger {
+	// the cluster name only annotates the log entries, a configuration error is reported by the operation itself
+	clusterName, _ := util.GetKubeClusterName(ctx, dp)
+	p := &phaseLogger{operation: operation, clusterName: clusterName, start: time.Now()}
+	tflog.Info(ctx, "dataplane "+operation+" started", map[string]any{"cluster": clusterName})
+	return p
+}
+
+// begin ends the current phase and starts the next one. Reaching the ready phase only ends the current phase.
+func (p *phaseLogger) begin(ctx context.Context, phase string) {
+	p.end(ctx, "completed")
+	if phase == awsconfig.PhaseReady {
+		return
+	}
+	p.phase, p.phaseStart = phase, time.Now()
+	tflog.Info(ctx, "dataplane "+p.operation+" phase started", map[string]any{"phase": phase, "cluster": p.clusterName})
+}
+
+func (p *phaseLogger) end(ctx context.Context, result string) {
+	if p.phase == "" {
+		return
+	}
+	tflog.Info(ctx, "dataplane "+p.operation+" phase "+result, map[string]any{
+		"phase":   p.phase,
+		"cluster": p.clusterName,
+		"elapsed": time.Since(p.phaseStart).Round(time.Second).String(),
+	})
+	p.phase = ""
+}
+
+// finish ends the current phase and the operation, which failed when the diagnostics hold an error.
+func (p *phaseLogger) finish(ctx context.Context, d diag.Diagnostics) {
+	result := "completed"
+	if d.HasError() {
+		result = "failed"
+	}
+	p.end(ctx, result)
+	tflog.Info(ctx, "dataplane "+p.operation+" "+result, map[string]any{
+		"cluster": p.clusterName,
+		"elapsed": time.Since(p.start).Round(time.Second).String(),
+	})
+}
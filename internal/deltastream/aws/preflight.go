@@ -0,0 +1,278 @@
+// Copyright (c) DeltaStream, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package aws
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alitto/pond"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	iamtypes "github.com/aws/aws-sdk-go-v2/service/iam/types"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	kmstypes "github.com/aws/aws-sdk-go-v2/service/kms/types"
+	"github.com/aws/aws-sdk-go-v2/service/route53"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	awsconfig "github.com/deltastreaminc/terraform-provider-dataplane/internal/deltastream/aws/config"
+	"github.com/deltastreaminc/terraform-provider-dataplane/internal/deltastream/aws/util"
+)
+
+// preflightChecks verifies that the AWS resources referenced by the cluster configuration exist and are accessible
+// with the assumed role. All failures are collected so that they can be reported at once.
+func preflightChecks(ctx context.Context, cfg aws.Config, dp awsconfig.AWSDataplane) (d diag.Diagnostics) {
+	clusterConfig, diags := dp.ClusterConfigurationData(ctx)
+	d.Append(diags...)
+	if d.HasError() {
+		return
+	}
+
+	tflog.Debug(ctx, "running preflight checks")
+
+	dynamodbClient := dynamodb.NewFromConfig(cfg)
+	if _, err := dynamodbClient.DescribeTable(ctx, &dynamodb.DescribeTableInput{
+		TableName: aws.String(clusterConfig.DynamoDbTableName.ValueString()),
+	}); err != nil {
+		d.AddError("preflight: unable to access DynamoDB table "+clusterConfig.DynamoDbTableName.ValueString(), util.AwsErrorDetail(err, util.Remediation{Action: "dynamodb:DescribeTable", Attribute: "dynamodb_table_name"}))
+	}
+
+	d.Append(checkProductVersion(ctx, cfg, clusterConfig)...)
+
+	d.Append(checkKmsKey(ctx, kms.NewFromConfig(cfg), cfg.Region, clusterConfig.KmsKeyId.ValueString())...)
+
+	d.Append(checkIamRoles(ctx, iam.NewFromConfig(cfg), clusterConfig)...)
+
+	// a managed interruption queue is created after the preflight checks
+	if !clusterConfig.ManageInterruptionQueue.ValueBool() {
+		sqsClient := sqs.NewFromConfig(cfg)
+		if _, err := sqsClient.GetQueueUrl(ctx, &sqs.GetQueueUrlInput{
+			QueueName: aws.String(clusterConfig.InterruptionQueueName.ValueString()),
+		}); err != nil {
+			d.AddError("preflight: unable to access SQS queue "+clusterConfig.InterruptionQueueName.ValueString(), util.AwsErrorDetail(err, util.Remediation{Action: "sqs:GetQueueUrl", Attribute: "interruption_queue_name"}))
+		}
+	}
+
+	s3Client := s3.NewFromConfig(cfg)
+	buckets := []struct{ attr, name string }{
+		{"product_artifacts_bucket", clusterConfig.ProductArtifactsBucket.ValueString()},
+		{"workload_state_bucket", clusterConfig.WorkloadStateBucket.ValueString()},
+	}
+	for _, bucket := range buckets {
+		if _, err := s3Client.HeadBucket(ctx, &s3.HeadBucketInput{Bucket: aws.String(bucket.name)}); err != nil {
+			d.AddError("preflight: unable to access S3 bucket "+bucket.name, util.AwsErrorDetail(err, util.Remediation{Action: "s3:ListBucket", Attribute: bucket.attr}))
+		}
+	}
+
+	// serde bucket defaults to the DeltaStream region
+	serdeCfg := cfg.Copy()
+	serdeCfg.Region = util.SerdeBucketRegion(cfg, clusterConfig)
+	if _, err := s3.NewFromConfig(serdeCfg).HeadBucket(ctx, &s3.HeadBucketInput{
+		Bucket: aws.String(clusterConfig.SerdeBucket.ValueString()),
+	}); err != nil {
+		d.AddError("preflight: unable to access S3 bucket "+clusterConfig.SerdeBucket.ValueString(), util.AwsErrorDetail(err, util.Remediation{Action: "s3:ListBucket", Attribute: "serde_bucket"}))
+	}
+
+	o11yCfg := cfg.Copy()
+	o11yCfg.Region = util.O11yBucketRegion(cfg, clusterConfig)
+	if _, err := s3.NewFromConfig(o11yCfg).HeadBucket(ctx, &s3.HeadBucketInput{
+		Bucket: aws.String(clusterConfig.O11yBucket.ValueString()),
+	}); err != nil {
+		d.AddError("preflight: unable to access S3 bucket "+clusterConfig.O11yBucket.ValueString(), util.AwsErrorDetail(err, util.Remediation{Action: "s3:ListBucket", Attribute: "o11y_bucket"}))
+	}
+
+	if clusterConfig.ValidateDns.ValueBool() {
+		d.Append(checkHostnameZones(ctx, route53.NewFromConfig(cfg), clusterConfig)...)
+	}
+
+	if !d.HasError() {
+		tflog.Debug(ctx, "preflight checks passed")
+	}
+	return
+}
+
+// checkKmsKey verifies that the KMS key exists in the region, is an enabled symmetric encryption key and that the
+// current principal may use it to generate data keys.
+func checkKmsKey(ctx context.Context, kmsClient *kms.Client, region string, keyId string) (d diag.Diagnostics) {
+	summary := "preflight: KMS key " + keyId + " is not usable"
+	if keyArn, err := arn.Parse(keyId); err == nil && keyArn.Region != region {
+		d.AddError(summary, fmt.Sprintf("the key is in region %s, the dataplane is in region %s", keyArn.Region, region))
+		return
+	}
+
+	describeOut, err := kmsClient.DescribeKey(ctx, &kms.DescribeKeyInput{KeyId: aws.String(keyId)})
+	if err != nil {
+		d.AddError("preflight: unable to access KMS key "+keyId, util.AwsErrorDetail(err, util.Remediation{Action: "kms:DescribeKey", Attribute: "kms_key_id"}))
+		return
+	}
+	metadata := describeOut.KeyMetadata
+	if metadata.KeyState != kmstypes.KeyStateEnabled {
+		d.AddError(summary, fmt.Sprintf("the key state is %s, it must be %s", metadata.KeyState, kmstypes.KeyStateEnabled))
+		return
+	}
+	if metadata.KeyUsage != kmstypes.KeyUsageTypeEncryptDecrypt || metadata.KeySpec != kmstypes.KeySpecSymmetricDefault {
+		d.AddError(summary, fmt.Sprintf("the key is a %s key for %s, a %s key for %s is required", metadata.KeySpec, metadata.KeyUsage, kmstypes.KeySpecSymmetricDefault, kmstypes.KeyUsageTypeEncryptDecrypt))
+		return
+	}
+
+	// the generated data key is discarded, the call only proves that the key policy and grants allow its use
+	if _, err := kmsClient.GenerateDataKey(ctx, &kms.GenerateDataKeyInput{
+		KeyId:   aws.String(keyId),
+		KeySpec: kmstypes.DataKeySpecAes256,
+	}); err != nil {
+		d.AddError(summary, "unable to generate a data key with the assumed role: "+util.AwsErrorDetail(err, util.Remediation{Action: "kms:GenerateDataKey", Attribute: "kms_key_id"}))
+	}
+	return
+}
+
+const rdsConnectivityTimeout = 10 * time.Second
+
+// checkIamRoles verifies that the IAM roles of the dataplane account referenced by the cluster configuration exist.
+// Roles of other accounts cannot be inspected and are skipped. Roles that cannot be read with the assumed role are
+// reported as a warning, missing roles as a single error.
+func checkIamRoles(ctx context.Context, iamClient *iam.Client, clusterConfig awsconfig.ClusterConfiguration) (d diag.Diagnostics) {
+	roleArns := map[string]basetypes.StringValue{
+		"aws_secrets_manager_ro_role_arn":       clusterConfig.AwsSecretsManagerRoRoleARN,
+		"infra_manager_role_arn":                clusterConfig.InfraManagerRoleArn,
+		"vault_role_arn":                        clusterConfig.VaultRoleArn,
+		"vault_init_role_arn":                   clusterConfig.VaultInitRoleArn,
+		"loki_role_arn":                         clusterConfig.LokiRoleArn,
+		"tempo_role_arn":                        clusterConfig.TempoRoleArn,
+		"thanos_store_gateway_role_arn":         clusterConfig.ThanosStoreGatewayRoleArn,
+		"thanos_store_compactor_role_arn":       clusterConfig.ThanosStoreCompactorRoleArn,
+		"thanos_store_bucket_role_arn":          clusterConfig.ThanosStoreBucketRoleArn,
+		"thanos_sidecar_role_arn":               clusterConfig.ThanosSidecarRoleArn,
+		"deadman_alert_role_arn":                clusterConfig.DeadmanAlertRoleArn,
+		"karpenter_irsa_role_arn":               clusterConfig.KarpenterIrsaRoleArn,
+		"store_proxy_role_arn":                  clusterConfig.StoreProxyRoleArn,
+		"cw2loki_role_arn":                      clusterConfig.Cw2LokiRoleArn,
+		"ecr_readonly_role_arn":                 clusterConfig.EcrReadonlyRoleArn,
+		"dp_manager_role_arn":                   clusterConfig.DpManagerRoleArn,
+		"kafka_role_arn":                        clusterConfig.KafkaRoleArn,
+		"aws_load_balancer_controller_role_arn": clusterConfig.AwsLoadBalancerControllerRoleARN,
+		"datagen_role_arn":                      clusterConfig.DatagenRoleArn,
+		"nth_role_arn":                          clusterConfig.NthRoleArn,
+		"workload_role_arn":                     clusterConfig.WorkloadRoleArn,
+		"workload_manager_role_arn":             clusterConfig.WorkloadManagerRoleArn,
+		"custom_credentials_role_arn":           clusterConfig.CustomCredentialsRoleARN,
+		"rds_iam_role_arn":                      clusterConfig.RdsIamRoleArn,
+	}
+
+	roleNames := map[string]string{}
+	if name := clusterConfig.KarpenterNodeRoleName.ValueString(); name != "" {
+		roleNames["karpenter_node_role_name"] = name
+	}
+	for attr, v := range roleArns {
+		if v.IsNull() || v.IsUnknown() || v.ValueString() == "" {
+			continue
+		}
+		roleArn, err := arn.Parse(v.ValueString())
+		if err != nil || !strings.HasPrefix(roleArn.Resource, "role/") {
+			d.AddAttributeError(configurationPath.AtName(attr), "preflight: invalid role ARN", fmt.Sprintf("%s is not an IAM role ARN", v.ValueString()))
+			continue
+		}
+		if roleArn.AccountID != clusterConfig.AccountId.ValueString() {
+			continue
+		}
+		roleNames[attr] = roleArn.Resource[strings.LastIndex(roleArn.Resource, "/")+1:]
+	}
+
+	var mu sync.Mutex
+	missing, inaccessible := []string{}, []string{}
+	pool := pond.New(5, 100)
+	for attr, name := range roleNames {
+		pool.Submit(func() {
+			_, err := iamClient.GetRole(ctx, &iam.GetRoleInput{RoleName: aws.String(name)})
+			if err == nil {
+				return
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			var noSuchEntityException *iamtypes.NoSuchEntityException
+			if errors.As(err, &noSuchEntityException) {
+				missing = append(missing, fmt.Sprintf("%s: role %s does not exist", attr, name))
+				return
+			}
+			inaccessible = append(inaccessible, fmt.Sprintf("%s: %s", attr, err.Error()))
+		})
+	}
+	pool.StopAndWait()
+
+	sort.Strings(missing)
+	sort.Strings(inaccessible)
+	if len(missing) > 0 {
+		d.AddError("preflight: IAM roles not found", strings.Join(missing, "\n"))
+	}
+	if len(inaccessible) > 0 {
+		d.AddWarning("preflight: unable to verify IAM roles", strings.Join(inaccessible, "\n"))
+	}
+	return
+}
+
+// postgresSSLRequestCode is sent by a client to ask a Postgres server to upgrade the connection to TLS.
+const postgresSSLRequestCode = 80877103
+
+// checkRdsConnectivity opens a TCP connection to the RDS instance and, when withTLS is set, negotiates TLS using the
+// Postgres SSLRequest handshake. The server certificate is not verified as the RDS CA is not a public root, the check
+// only establishes that the instance is reachable and accepts TLS.
+func checkRdsConnectivity(ctx context.Context, host string, port int, withTLS bool) (d diag.Diagnostics) {
+	address := net.JoinHostPort(host, strconv.Itoa(port))
+	tflog.Debug(ctx, "checking rds connectivity", map[string]any{"address": address, "tls": withTLS})
+
+	ctx, cancel := context.WithTimeout(ctx, rdsConnectivityTimeout)
+	defer cancel()
+
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", address)
+	if err != nil {
+		d.AddError("preflight: unable to connect to RDS instance "+address, "Verify the host and port in the RDS credentials secret and that the security groups allow access from the Terraform host: "+err.Error())
+		return
+	}
+	defer conn.Close()
+	if !withTLS {
+		return
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+	req := make([]byte, 8)
+	binary.BigEndian.PutUint32(req[0:4], 8)
+	binary.BigEndian.PutUint32(req[4:8], postgresSSLRequestCode)
+	if _, err := conn.Write(req); err != nil {
+		d.AddError("preflight: unable to negotiate TLS with RDS instance "+address, err.Error())
+		return
+	}
+	resp := make([]byte, 1)
+	if _, err := io.ReadFull(conn, resp); err != nil {
+		d.AddError("preflight: unable to negotiate TLS with RDS instance "+address, err.Error())
+		return
+	}
+	if resp[0] != 'S' {
+		d.AddError("preflight: RDS instance "+address+" does not accept TLS", fmt.Sprintf("unexpected SSLRequest response %q", resp[0]))
+		return
+	}
+
+	tlsConn := tls.Client(conn, &tls.Config{ServerName: host, InsecureSkipVerify: true})
+	if err := tlsConn.HandshakeContext(ctx); err != nil {
+		d.AddError("preflight: TLS handshake with RDS instance "+address+" failed", err.Error())
+		return
+	}
+	return
+}
@@ -8,8 +8,14 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/net/http/httpproxy"
 
 	"github.com/deltastreaminc/terraform-provider-dataplane/internal/config"
 	awsconfig "github.com/deltastreaminc/terraform-provider-dataplane/internal/deltastream/aws/config"
@@ -18,13 +24,36 @@ import (
 
 var _ resource.Resource = &AWSDataplaneResource{}
 var _ resource.ResourceWithConfigure = &AWSDataplaneResource{}
+var _ resource.ResourceWithValidateConfig = &AWSDataplaneResource{}
+var _ resource.ResourceWithModifyPlan = &AWSDataplaneResource{}
+var _ resource.ResourceWithUpgradeState = &AWSDataplaneResource{}
 
 func NewAWSDataplaneResource() resource.Resource {
-	return &AWSDataplaneResource{}
+	d := &AWSDataplaneResource{}
+	d.getAwsConfig = func(ctx context.Context, dp awsconfig.AWSDataplane) (aws.Config, diag.Diagnostics) {
+		return util.GetAwsConfig(ctx, d.settings, dp)
+	}
+	d.getKubeClient = func(ctx context.Context, cfg aws.Config, dp awsconfig.AWSDataplane) (*util.RetryableClient, diag.Diagnostics) {
+		return util.GetKubeClient(ctx, d.settings, cfg, dp)
+	}
+	return d
 }
 
+// awsConfigFactory returns the AWS config used to manage a dataplane.
+type awsConfigFactory func(ctx context.Context, dp awsconfig.AWSDataplane) (aws.Config, diag.Diagnostics)
+
+// kubeClientFactory returns a client for the dataplane cluster. Long running phases call it repeatedly so that
+// expired credentials are refreshed.
+type kubeClientFactory func(ctx context.Context, cfg aws.Config, dp awsconfig.AWSDataplane) (*util.RetryableClient, diag.Diagnostics)
+
 type AWSDataplaneResource struct {
 	infraVersion string
+	settings     util.ClientSettings
+	defaultTags  map[string]string
+
+	// client constructors, replaced in tests
+	getAwsConfig  awsConfigFactory
+	getKubeClient kubeClientFactory
 }
 
 // Schema implements resource.Resource.
@@ -48,6 +77,83 @@ func (d *AWSDataplaneResource) Configure(ctx context.Context, req resource.Confi
 	}
 
 	d.infraVersion = cfg.Version
+	d.settings = clientSettings(cfg)
+	d.defaultTags = util.MergeTags(cfg.DefaultTags)
+}
+
+// clientSettings returns the AWS and kube client settings of the provider configuration.
+func clientSettings(cfg *config.DataplaneResourceData) util.ClientSettings {
+	return util.ClientSettings{
+		Proxy: httpproxy.Config{
+			HTTPProxy:  cfg.HTTPProxy,
+			HTTPSProxy: cfg.HTTPSProxy,
+			NoProxy:    cfg.NoProxy,
+		},
+		AwsMaxAttempts:    cfg.AwsMaxAttempts,
+		AwsRequestTimeout: cfg.AwsRequestTimeout,
+		KubeMaxRetries:    cfg.KubeMaxRetries,
+		KubeMaxBackoff:    cfg.KubeMaxBackoff,
+		KubeQPS:           cfg.KubeQPS,
+		KubeBurst:         cfg.KubeBurst,
+		DryRun:            cfg.DryRun,
+	}
+}
+
+// ValidateConfig implements resource.ResourceWithValidateConfig.
+func (d *AWSDataplaneResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var dp awsconfig.AWSDataplane
+	resp.Diagnostics.Append(req.Config.Get(ctx, &dp)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(validateConfiguration(ctx, dp)...)
+}
+
+// ModifyPlan implements resource.ResourceWithModifyPlan.
+func (d *AWSDataplaneResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// nothing to report on create or destroy
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var oldDp, newDp awsconfig.AWSDataplane
+	resp.Diagnostics.Append(req.State.Get(ctx, &oldDp)...)
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &newDp)...)
+	if resp.Diagnostics.HasError() || newDp.ClusterConfiguration.IsUnknown() {
+		return
+	}
+
+	oldClusterConfig, diags := oldDp.ClusterConfigurationData(ctx)
+	resp.Diagnostics.Append(diags...)
+	newClusterConfig, diags := newDp.ClusterConfigurationData(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// images are mirrored on demand by the pull-through cache rule
+	if newClusterConfig.ImageDeliveryMode.ValueString() == awsconfig.ImageDeliveryModePullThrough {
+		return
+	}
+	if oldClusterConfig.ProductVersion.Equal(newClusterConfig.ProductVersion) && oldClusterConfig.AccountId.Equal(newClusterConfig.AccountId) {
+		return
+	}
+	if newClusterConfig.ProductVersion.IsUnknown() || newClusterConfig.AccountId.IsUnknown() {
+		resp.Diagnostics.AddWarning("Images will be copied", "product_version or account_id changed, product images will be copied to the dataplane account during apply.")
+		return
+	}
+
+	// counting images is best effort, the plan is not failed when the image lists cannot be read
+	detail := "product_version or account_id changed, product images will be copied to the dataplane account during apply."
+	if cfg, diags := d.getAwsConfig(ctx, newDp); !diags.HasError() {
+		if count, diags := plannedImageCopies(ctx, cfg, oldClusterConfig, newClusterConfig); !diags.HasError() {
+			detail = fmt.Sprintf("product_version or account_id changed, %d image(s) will be copied to the dataplane account during apply.", count)
+		} else {
+			tflog.Debug(ctx, "unable to determine images to copy", map[string]any{"diagnostics": diags})
+		}
+	}
+	resp.Diagnostics.AddWarning("Images will be copied", detail)
 }
 
 func (d *AWSDataplaneResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -64,87 +170,122 @@ func (d *AWSDataplaneResource) Create(ctx context.Context, req resource.CreateRe
 		return
 	}
 
-	cfg, diags := util.GetAwsConfig(ctx, dp)
+	cfg, diags := d.getAwsConfig(ctx, dp)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	// copy images
-	resp.Diagnostics.Append(copyImages(ctx, cfg, dp)...)
+	if d.settings.DryRun {
+		resp.Diagnostics.Append(d.dryRun(ctx, cfg, dp)...)
+		return
+	}
+
+	phases := newPhaseLogger(ctx, "create", dp)
+	defer func() { phases.finish(ctx, resp.Diagnostics) }()
+
+	// the creation time is carried forward by every later status update
+	status := &awsconfig.Status{CreatedAt: basetypes.NewStringValue(time.Now().Format(time.RFC3339))}
+	dp.Status, diags = basetypes.NewObjectValueFrom(ctx, status.AttributeTypes(), status)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// progress is only persisted once DeltaStream is being installed. A create that fails earlier leaves no state, so
+	// the next apply retries the create instead of destroying a cluster DeltaStream was never installed into.
+
+	// verify referenced resources exist
+	resp.Diagnostics.Append(d.setPhase(ctx, phases, nil, &dp, awsconfig.PhasePreflight)...)
+	resp.Diagnostics.Append(preflightChecks(ctx, cfg, dp)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(ensureInterruptionQueue(ctx, cfg, dp, d.defaultTags)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(checkNodeProvisioning(ctx, cfg, dp, d.getKubeClient)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
 	// copy images
+	resp.Diagnostics.Append(d.setPhase(ctx, phases, nil, &dp, awsconfig.PhaseCopyingImages)...)
+	resp.Diagnostics.Append(copyImages(ctx, cfg, dp, d.defaultTags)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// update role trust policies
+	resp.Diagnostics.Append(d.setPhase(ctx, phases, nil, &dp, awsconfig.PhaseUpdatingTrustPolicies)...)
 	resp.Diagnostics.Append(updateRoleTrustPolicies(ctx, cfg, dp)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
 	// remove aws-node
-	resp.Diagnostics.Append(deleteAwsNode(ctx, cfg, dp)...)
+	resp.Diagnostics.Append(d.setPhase(ctx, phases, nil, &dp, awsconfig.PhaseRemovingAwsNode)...)
+	resp.Diagnostics.Append(deleteAwsNode(ctx, cfg, dp, d.getKubeClient)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
 	// install cilium
-	resp.Diagnostics.Append(installCilium(ctx, cfg, dp)...)
+	resp.Diagnostics.Append(d.setPhase(ctx, phases, nil, &dp, awsconfig.PhaseInstallingCilium)...)
+	resp.Diagnostics.Append(installCilium(ctx, d.settings, cfg, dp, d.getKubeClient, true)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
 	// update cluster-config
-	resp.Diagnostics.Append(updateClusterConfig(ctx, cfg, dp, d.infraVersion)...)
+	resp.Diagnostics.Append(d.setPhase(ctx, phases, nil, &dp, awsconfig.PhaseConfiguringCluster)...)
+	resp.Diagnostics.Append(updateClusterConfig(ctx, cfg, dp, d.getKubeClient, d.infraVersion)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
 	// start microservices
-	resp.Diagnostics.Append(installDeltaStream(ctx, cfg, dp)...)
+	resp.Diagnostics.Append(d.setPhase(ctx, phases, &resp.State, &dp, awsconfig.PhaseInstallingDeltaStream)...)
+	resp.Diagnostics.Append(installDeltaStream(ctx, cfg, dp, d.getKubeClient, d.infraVersion, d.defaultTags)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
 	// recover any failing microservices
-	resp.Diagnostics.Append(restartFluxReleases(ctx, cfg, dp)...)
+	resp.Diagnostics.Append(d.setPhase(ctx, phases, &resp.State, &dp, awsconfig.PhaseWaitingForServices)...)
+	resp.Diagnostics.Append(restartFluxReleases(ctx, cfg, dp, d.getKubeClient)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
 	// // wait for microservices
-	resp.Diagnostics.Append(waitKustomizations(ctx, cfg, dp)...)
+	resp.Diagnostics.Append(waitKustomizations(ctx, cfg, dp, d.getKubeClient)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
 	// // start custom credentials
-	resp.Diagnostics.Append(deployCustomCredentialsContiner(ctx, cfg, dp)...)
+	resp.Diagnostics.Append(d.setPhase(ctx, phases, &resp.State, &dp, awsconfig.PhaseDeployingCustomCredentials)...)
+	resp.Diagnostics.Append(deployCustomCredentialsContiner(ctx, cfg, dp, d.getKubeClient)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	clusterConfig, diags := dp.ClusterConfigurationData(ctx)
-	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(d.setPhase(ctx, phases, &resp.State, &dp, awsconfig.PhaseVerifyingWorkloads)...)
+	resp.Diagnostics.Append(waitWorkloadsReady(ctx, cfg, dp, d.getKubeClient)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	status := &awsconfig.Status{
-		ProviderVersion: basetypes.NewStringValue(d.infraVersion),
-		ProductVersion:  clusterConfig.ProductVersion,
-		LastModified:    basetypes.NewStringValue(time.Now().Format(time.RFC3339)),
-	}
-	dp.Status, diags = basetypes.NewObjectValueFrom(ctx, status.AttributeTypes(), status)
-	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(setClusterStatus(ctx, cfg, &dp)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	resp.Diagnostics.Append(resp.State.Set(ctx, &dp)...)
-	if resp.Diagnostics.HasError() {
-		return
-	}
+	resp.Diagnostics.Append(d.setPhase(ctx, phases, &resp.State, &dp, awsconfig.PhaseReady)...)
 }
 
 func (d *AWSDataplaneResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
@@ -155,97 +296,306 @@ func (d *AWSDataplaneResource) Delete(ctx context.Context, req resource.DeleteRe
 		return
 	}
 
-	cfg, diags := util.GetAwsConfig(ctx, dp)
+	if d.settings.DryRun {
+		resp.Diagnostics.AddError("dry run", "dry_run is enabled on the provider, the dataplane was not destroyed")
+		return
+	}
+
+	cfg, diags := d.getAwsConfig(ctx, dp)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	resp.Diagnostics.Append(cleanup(ctx, cfg, dp)...)
+	resp.Diagnostics.Append(cleanup(ctx, cfg, dp, d.getKubeClient)...)
 }
 
 func (d *AWSDataplaneResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	var newDp awsconfig.AWSDataplane
+	var oldDp, newDp awsconfig.AWSDataplane
 
-	// Read Terraform plan data into the model
+	// Read Terraform prior state and plan data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &oldDp)...)
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &newDp)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	cfg, diags := util.GetAwsConfig(ctx, newDp)
+	cfg, diags := d.getAwsConfig(ctx, newDp)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	// // update cluster-config
-	resp.Diagnostics.Append(updateClusterConfig(ctx, cfg, newDp, d.infraVersion)...)
-	if resp.Diagnostics.HasError() {
+	if d.settings.DryRun {
+		resp.Diagnostics.Append(d.dryRun(ctx, cfg, newDp)...)
 		return
 	}
 
-	// copy images
-	resp.Diagnostics.Append(copyImages(ctx, cfg, newDp)...)
-	if resp.Diagnostics.HasError() {
-		return
-	}
+	phases := newPhaseLogger(ctx, "update", newDp)
+	defer func() { phases.finish(ctx, resp.Diagnostics) }()
 
-	// update microservices
-	resp.Diagnostics.Append(installDeltaStream(ctx, cfg, newDp)...)
+	oldClusterConfig, diags := oldDp.ClusterConfigurationData(ctx)
+	resp.Diagnostics.Append(diags...)
+	newClusterConfig, diags := newDp.ClusterConfigurationData(ctx)
+	resp.Diagnostics.Append(diags...)
+	var oldStatus awsconfig.Status
+	resp.Diagnostics.Append(oldDp.Status.As(ctx, &oldStatus, basetypes.ObjectAsOptions{UnhandledNullAsEmpty: true, UnhandledUnknownAsEmpty: true})...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
-
-	// recover any failing microservices
-	resp.Diagnostics.Append(restartFluxReleases(ctx, cfg, newDp)...)
+	// the planned status is unknown, start from the prior status so that created_at is preserved
+	newDp.Status = oldDp.Status
+
+	configChanged := !oldDp.ClusterConfiguration.Equal(newDp.ClusterConfiguration) || !oldDp.AssumeRole.Equal(newDp.AssumeRole) || !oldDp.Tags.Equal(newDp.Tags) || oldStatus.ProviderVersion.ValueString() != d.infraVersion
+	imagesChanged := !oldDp.AssumeRole.Equal(newDp.AssumeRole) || imageInputsChanged(oldClusterConfig, newClusterConfig)
+	// a new provider version can bundle a different Cilium chart
+	ciliumChanged := ciliumInputsChanged(oldClusterConfig, newClusterConfig) || oldStatus.ProviderVersion.ValueString() != d.infraVersion
+
+	// catch an invalid product version before the cluster is changed
+	if imagesChanged {
+		resp.Diagnostics.Append(checkProductVersion(ctx, cfg, newClusterConfig)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	// the deployment config is rendered with the new key, verify it before the cluster is changed
+	if !oldClusterConfig.KmsKeyId.Equal(newClusterConfig.KmsKeyId) || !oldDp.AssumeRole.Equal(newDp.AssumeRole) {
+		resp.Diagnostics.Append(checkKmsKey(ctx, kms.NewFromConfig(cfg), cfg.Region, newClusterConfig.KmsKeyId.ValueString())...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	if configChanged {
+		resp.Diagnostics.Append(ensureInterruptionQueue(ctx, cfg, newDp, d.defaultTags)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	skippedPhases := []string{}
+	// progress is recorded against the prior state so that a failed update is retried by the next apply
+	if configChanged {
+		// // update cluster-config
+		resp.Diagnostics.Append(d.setPhase(ctx, phases, &resp.State, &oldDp, awsconfig.PhaseConfiguringCluster)...)
+		resp.Diagnostics.Append(updateClusterConfig(ctx, cfg, newDp, d.getKubeClient, d.infraVersion)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	} else {
+		skippedPhases = append(skippedPhases, "update cluster config")
+	}
+
+	if imagesChanged {
+		// copy images
+		resp.Diagnostics.Append(d.setPhase(ctx, phases, &resp.State, &oldDp, awsconfig.PhaseCopyingImages)...)
+		resp.Diagnostics.Append(copyImages(ctx, cfg, newDp, d.defaultTags)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	} else {
+		skippedPhases = append(skippedPhases, "copy images")
+	}
+
+	if ciliumChanged {
+		// upgrade cilium
+		resp.Diagnostics.Append(d.setPhase(ctx, phases, &resp.State, &oldDp, awsconfig.PhaseInstallingCilium)...)
+		resp.Diagnostics.Append(installCilium(ctx, d.settings, cfg, newDp, d.getKubeClient, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	} else {
+		skippedPhases = append(skippedPhases, "upgrade cilium")
+	}
+
+	if configChanged {
+		// update microservices
+		resp.Diagnostics.Append(d.setPhase(ctx, phases, &resp.State, &oldDp, awsconfig.PhaseInstallingDeltaStream)...)
+		resp.Diagnostics.Append(installDeltaStream(ctx, cfg, newDp, d.getKubeClient, d.infraVersion, d.defaultTags)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		// recover any failing microservices
+		resp.Diagnostics.Append(d.setPhase(ctx, phases, &resp.State, &oldDp, awsconfig.PhaseWaitingForServices)...)
+		resp.Diagnostics.Append(restartFluxReleases(ctx, cfg, newDp, d.getKubeClient)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		// wait for microservices
+		resp.Diagnostics.Append(waitKustomizations(ctx, cfg, newDp, d.getKubeClient)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		// restart the workloads still holding credentials of the previous mode
+		if workloadCredentialsModeChanged(oldClusterConfig, newClusterConfig) {
+			resp.Diagnostics.Append(rolloutWorkloadCredentials(ctx, cfg, newDp, d.getKubeClient)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+		}
+
+		// update custom credentials
+		resp.Diagnostics.Append(d.setPhase(ctx, phases, &resp.State, &oldDp, awsconfig.PhaseDeployingCustomCredentials)...)
+		resp.Diagnostics.Append(deployCustomCredentialsContiner(ctx, cfg, newDp, d.getKubeClient)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		resp.Diagnostics.Append(d.setPhase(ctx, phases, &resp.State, &oldDp, awsconfig.PhaseVerifyingWorkloads)...)
+		resp.Diagnostics.Append(waitWorkloadsReady(ctx, cfg, newDp, d.getKubeClient)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	} else {
+		skippedPhases = append(skippedPhases, "install deltastream", "restart flux releases", "wait for services", "deploy custom credentials", "verify workloads")
+	}
+
+	if len(skippedPhases) > 0 {
+		tflog.Info(ctx, "skipped unchanged update phases", map[string]any{"phases": skippedPhases})
+	}
+
+	resp.Diagnostics.Append(setClusterStatus(ctx, cfg, &newDp)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	// wait for microservices
-	resp.Diagnostics.Append(waitKustomizations(ctx, cfg, newDp)...)
+	resp.Diagnostics.Append(d.setPhase(ctx, phases, &resp.State, &newDp, awsconfig.PhaseReady)...)
+}
+
+func (d *AWSDataplaneResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var dp awsconfig.AWSDataplane
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &dp)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	// update custom credentials
-	resp.Diagnostics.Append(deployCustomCredentialsContiner(ctx, cfg, newDp)...)
-	if resp.Diagnostics.HasError() {
+	resp.Diagnostics.Append(resp.State.Set(ctx, dp)...)
+}
+
+// imageInputsChanged reports whether any configuration used to locate and copy product images has changed.
+func imageInputsChanged(oldConfig, newConfig awsconfig.ClusterConfiguration) bool {
+	return !oldConfig.ProductVersion.Equal(newConfig.ProductVersion) ||
+		!oldConfig.Stack.Equal(newConfig.Stack) ||
+		!oldConfig.AccountId.Equal(newConfig.AccountId) ||
+		!oldConfig.DsAccountId.Equal(newConfig.DsAccountId) ||
+		!oldConfig.ProductArtifactsBucket.Equal(newConfig.ProductArtifactsBucket) ||
+		!oldConfig.ImageList.Equal(newConfig.ImageList) ||
+		!oldConfig.ImageDeliveryMode.Equal(newConfig.ImageDeliveryMode) ||
+		!oldConfig.ImageReplicaRegions.Equal(newConfig.ImageReplicaRegions) ||
+		!oldConfig.ImageReplicaBestEffort.Equal(newConfig.ImageReplicaBestEffort)
+}
+
+// ciliumInputsChanged reports whether any configuration used to select the Cilium chart has changed.
+func ciliumInputsChanged(oldConfig, newConfig awsconfig.ClusterConfiguration) bool {
+	return !oldConfig.CiliumVersion.Equal(newConfig.CiliumVersion) ||
+		!oldConfig.CiliumChartRepository.Equal(newConfig.CiliumChartRepository)
+}
+
+// setPhase records the phase reached in the resource status and persists it to state, so the last phase reached
+// remains in state if a later step fails. With a nil state the phase is only recorded in the status.
+func (d *AWSDataplaneResource) setPhase(ctx context.Context, phases *phaseLogger, state *tfsdk.State, dp *awsconfig.AWSDataplane, phase string) (diags diag.Diagnostics) {
+	phases.begin(ctx, phase)
+
+	clusterConfig, dg := dp.ClusterConfigurationData(ctx)
+	diags.Append(dg...)
+	if diags.HasError() {
 		return
 	}
 
-	clusterConfig, diags := newDp.ClusterConfigurationData(ctx)
-	resp.Diagnostics.Append(diags...)
-	if resp.Diagnostics.HasError() {
+	var prevStatus awsconfig.Status
+	diags.Append(dp.Status.As(ctx, &prevStatus, basetypes.ObjectAsOptions{UnhandledNullAsEmpty: true, UnhandledUnknownAsEmpty: true})...)
+	if diags.HasError() {
 		return
 	}
 
 	status := &awsconfig.Status{
 		ProviderVersion: basetypes.NewStringValue(d.infraVersion),
 		ProductVersion:  clusterConfig.ProductVersion,
+		CreatedAt:       prevStatus.CreatedAt,
 		LastModified:    basetypes.NewStringValue(time.Now().Format(time.RFC3339)),
+		Phase:           basetypes.NewStringValue(phase),
+		ClusterEndpoint: prevStatus.ClusterEndpoint,
+		OidcIssuer:      prevStatus.OidcIssuer,
 	}
-	newDp.Status, diags = basetypes.NewObjectValueFrom(ctx, status.AttributeTypes(), status)
-	resp.Diagnostics.Append(diags...)
-	if resp.Diagnostics.HasError() {
+	dp.Status, dg = basetypes.NewObjectValueFrom(ctx, status.AttributeTypes(), status)
+	diags.Append(dg...)
+	if diags.HasError() {
 		return
 	}
 
-	resp.Diagnostics.Append(resp.State.Set(ctx, &newDp)...)
-	if resp.Diagnostics.HasError() {
+	if state != nil {
+		diags.Append(state.Set(ctx, dp)...)
+	}
+	return
+}
+
+// setClusterStatus records the EKS cluster endpoint and OIDC issuer in the resource status.
+func setClusterStatus(ctx context.Context, cfg aws.Config, dp *awsconfig.AWSDataplane) (diags diag.Diagnostics) {
+	cluster, dg := util.DescribeKubeCluster(ctx, *dp, cfg)
+	diags.Append(dg...)
+	if diags.HasError() {
+		return
+	}
+
+	var status awsconfig.Status
+	diags.Append(dp.Status.As(ctx, &status, basetypes.ObjectAsOptions{UnhandledNullAsEmpty: true, UnhandledUnknownAsEmpty: true})...)
+	if diags.HasError() {
 		return
 	}
+
+	status.ClusterEndpoint = basetypes.NewStringPointerValue(cluster.Endpoint)
+	status.OidcIssuer = basetypes.NewStringNull()
+	if cluster.Identity != nil && cluster.Identity.Oidc != nil {
+		status.OidcIssuer = basetypes.NewStringPointerValue(cluster.Identity.Oidc.Issuer)
+	}
+
+	dp.Status, dg = basetypes.NewObjectValueFrom(ctx, status.AttributeTypes(), status)
+	diags.Append(dg...)
+	return
 }
 
-func (d *AWSDataplaneResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
-	var dp awsconfig.AWSDataplane
+// dataplaneTags returns the tags applied to AWS resources created for the dataplane, the provider default tags merged
+// with the resource tags.
+func dataplaneTags(ctx context.Context, dp awsconfig.AWSDataplane, defaultTags map[string]string) (map[string]string, diag.Diagnostics) {
+	tags, d := dp.TagsData(ctx)
+	return util.MergeTags(defaultTags, tags), d
+}
 
-	// Read Terraform plan data into the model
-	resp.Diagnostics.Append(req.State.Get(ctx, &dp)...)
-	if resp.Diagnostics.HasError() {
+// dryRun runs the preflight checks, renders the cluster settings, the deployment config and the manifests and applies
+// the manifests with server side dry run. The changes that would be made are reported as warnings. Images, IAM trust
+// policies, aws-node and Cilium are left untouched. The run always ends with an error so that Terraform does not
+// record the planned state.
+func (d *AWSDataplaneResource) dryRun(ctx context.Context, cfg aws.Config, dp awsconfig.AWSDataplane) (diags diag.Diagnostics) {
+	diags.Append(preflightChecks(ctx, cfg, dp)...)
+	if diags.HasError() {
 		return
 	}
 
-	resp.Diagnostics.Append(resp.State.Set(ctx, dp)...)
+	_, dg := d.getKubeClient(ctx, cfg, dp)
+	diags.Append(dg...)
+	if diags.HasError() {
+		return
+	}
+
+	diags.Append(updateClusterConfig(ctx, cfg, dp, d.getKubeClient, d.infraVersion)...)
+	if diags.HasError() {
+		return
+	}
+	diags.Append(installDeltaStream(ctx, cfg, dp, d.getKubeClient, d.infraVersion, d.defaultTags)...)
+	if diags.HasError() {
+		return
+	}
+	diags.Append(deployCustomCredentialsContiner(ctx, cfg, dp, d.getKubeClient)...)
+	if diags.HasError() {
+		return
+	}
+
+	diags.AddError("dry run completed", "dry_run is enabled on the provider, the configuration was validated and the changes reported above were not applied")
+	return
 }
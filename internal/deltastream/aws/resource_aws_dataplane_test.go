@@ -0,0 +1,60 @@
+// Copyright (c) DeltaStream, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package aws
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+
+	awsconfig "github.com/deltastreaminc/terraform-provider-dataplane/internal/deltastream/aws/config"
+	"github.com/deltastreaminc/terraform-provider-dataplane/internal/deltastream/aws/util"
+)
+
+// emptyPlan returns a plan with every top level attribute null.
+func emptyPlan(ctx context.Context) tfsdk.Plan {
+	schemaType := awsconfig.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	values := map[string]tftypes.Value{}
+	for name, attrType := range schemaType.AttributeTypes {
+		values[name] = tftypes.NewValue(attrType, nil)
+	}
+	return tfsdk.Plan{Schema: awsconfig.Schema, Raw: tftypes.NewValue(schemaType, values)}
+}
+
+func TestCreateStopsOnAwsConfigError(t *testing.T) {
+	ctx := context.Background()
+	kubeClientRequested := false
+	r := &AWSDataplaneResource{
+		getAwsConfig: func(ctx context.Context, dp awsconfig.AWSDataplane) (cfg aws.Config, d diag.Diagnostics) {
+			d.AddError("no credentials", "test")
+			return
+		},
+		getKubeClient: func(ctx context.Context, cfg aws.Config, dp awsconfig.AWSDataplane) (*util.RetryableClient, diag.Diagnostics) {
+			kubeClientRequested = true
+			return nil, nil
+		},
+	}
+
+	schemaType := awsconfig.Schema.Type().TerraformType(ctx)
+	resp := &resource.CreateResponse{State: tfsdk.State{Schema: awsconfig.Schema, Raw: tftypes.NewValue(schemaType, nil)}}
+	r.Create(ctx, resource.CreateRequest{Plan: emptyPlan(ctx)}, resp)
+
+	if !resp.Diagnostics.HasError() {
+		t.Fatal("Create() succeeded without AWS credentials")
+	}
+	if summary := resp.Diagnostics.Errors()[0].Summary(); summary != "no credentials" {
+		t.Errorf("Create() error = %q, want the AWS config error", summary)
+	}
+	if kubeClientRequested {
+		t.Error("Create() requested a kube client after failing to load the AWS config")
+	}
+	if !resp.State.Raw.IsNull() {
+		t.Error("Create() recorded state after failing to load the AWS config")
+	}
+}
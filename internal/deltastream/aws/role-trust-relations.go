@@ -3,15 +3,24 @@ package aws
 import (
 	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"html/template"
+	"net/url"
+	"reflect"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/iam"
+	iamtypes "github.com/aws/aws-sdk-go-v2/service/iam/types"
 	awsconfig "github.com/deltastreaminc/terraform-provider-dataplane/internal/deltastream/aws/config"
 	"github.com/deltastreaminc/terraform-provider-dataplane/internal/deltastream/aws/util"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
-	"k8s.io/utils/ptr"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/sethvargo/go-retry"
 )
 
 var trustRelationTemplate = `
@@ -52,17 +61,146 @@ func updateRoleTrustPolicy(ctx context.Context, cfg aws.Config, clusterConfig aw
 	}
 
 	iamclient := iam.NewFromConfig(cfg)
-	if _, err := iamclient.UpdateAssumeRolePolicy(ctx, &iam.UpdateAssumeRolePolicyInput{
-		RoleName:       aws.String(roleName),
-		PolicyDocument: aws.String(strings.TrimSpace(b.String())),
+	var getRoleOut *iam.GetRoleOutput
+	if err := retry.Do(ctx, trustPolicyBackoff(), func(ctx context.Context) (err error) {
+		getRoleOut, err = iamclient.GetRole(ctx, &iam.GetRoleInput{RoleName: aws.String(roleName)})
+		return retryableIamError(ctx, err)
 	}); err != nil {
-		d.AddError("failed to update role trust relation for role "+roleName, err.Error())
+		d.AddError("failed to read role "+roleName, util.AwsErrorDetail(err, util.Remediation{Action: "iam:GetRole"}))
+		return
+	}
+	currentPolicy := aws.ToString(getRoleOut.Role.AssumeRolePolicyDocument)
+	desiredPolicy := strings.TrimSpace(b.String())
+	if trustPoliciesEqual(currentPolicy, desiredPolicy) {
+		tflog.Debug(ctx, "trust policy of role "+roleName+" is up to date")
+		return
+	}
+
+	oidcProvider := fmt.Sprintf("oidc.eks.%s.amazonaws.com/id/%s", cfg.Region, issuerID)
+	subject := fmt.Sprintf("system:serviceaccount:%s:%s", serviceAccountNamespace, serviceAccountName)
+	conflicts, err := conflictingTrustSubjects(currentPolicy, oidcProvider, subject)
+	if err != nil {
+		d.AddWarning("unable to inspect the trust policy of role "+roleName, err.Error())
+	} else if len(conflicts) > 0 {
+		summary := "role " + roleName + " trusts other service accounts"
+		detail := fmt.Sprintf("The trust policy will be replaced to trust only %s of %s, removing trust for:\n  %s", subject, oidcProvider, strings.Join(conflicts, "\n  "))
+		if clusterConfig.StrictTrustPolicy.ValueBool() {
+			d.AddError(summary, detail+"\n\nUse a dedicated role for each component, or unset strict_trust_policy to replace the policy anyway.")
+			return
+		}
+		d.AddWarning(summary, detail)
+	}
+
+	if err := retry.Do(ctx, trustPolicyBackoff(), func(ctx context.Context) error {
+		_, err := iamclient.UpdateAssumeRolePolicy(ctx, &iam.UpdateAssumeRolePolicyInput{
+			RoleName:       aws.String(roleName),
+			PolicyDocument: aws.String(desiredPolicy),
+		})
+		return retryableIamError(ctx, err)
+	}); err != nil {
+		d.AddError("failed to update role trust relation for role "+roleName, util.AwsErrorDetail(err, util.Remediation{Action: "iam:UpdateAssumeRolePolicy"}))
 		return
 	}
 
 	return
 }
 
+// trustPolicyBackoff returns the backoff of IAM requests, a new one is needed for every retry.Do.
+func trustPolicyBackoff() retry.Backoff {
+	return retry.WithMaxRetries(5, retry.WithJitterPercent(20, retry.NewExponential(time.Second*2)))
+}
+
+// retryableIamError marks err as retryable unless IAM rejected the request outright.
+func retryableIamError(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+	var noSuchEntityException *iamtypes.NoSuchEntityException
+	var malformedPolicyDocumentException *iamtypes.MalformedPolicyDocumentException
+	if errors.As(err, &noSuchEntityException) || errors.As(err, &malformedPolicyDocumentException) {
+		return err
+	}
+	tflog.Debug(ctx, "retrying IAM request: "+err.Error())
+	return retry.RetryableError(err)
+}
+
+// trustPoliciesEqual reports whether the URL encoded policy document returned by IAM is semantically equal to the
+// desired policy document.
+func trustPoliciesEqual(current, desired string) bool {
+	decoded, err := url.QueryUnescape(current)
+	if err != nil {
+		return false
+	}
+	var currentDoc, desiredDoc any
+	if err := json.Unmarshal([]byte(decoded), &currentDoc); err != nil {
+		return false
+	}
+	if err := json.Unmarshal([]byte(desired), &desiredDoc); err != nil {
+		return false
+	}
+	return reflect.DeepEqual(currentDoc, desiredDoc)
+}
+
+// conflictingTrustSubjects returns the web identity subjects, as provider:subject, trusted by the policy document
+// other than the given subject of the given OIDC provider. IAM returns policy documents URL encoded.
+func conflictingTrustSubjects(policyDocument, oidcProvider, subject string) ([]string, error) {
+	decoded, err := url.QueryUnescape(policyDocument)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode trust policy: %w", err)
+	}
+	policy := struct {
+		Statement json.RawMessage
+	}{}
+	if err := json.Unmarshal([]byte(decoded), &policy); err != nil {
+		return nil, fmt.Errorf("unable to parse trust policy: %w", err)
+	}
+	type statement struct {
+		Effect    string
+		Condition map[string]map[string]any
+	}
+	statements := []statement{}
+	if err := json.Unmarshal(policy.Statement, &statements); err != nil {
+		single := statement{}
+		if err := json.Unmarshal(policy.Statement, &single); err != nil {
+			return nil, fmt.Errorf("unable to parse trust policy statements: %w", err)
+		}
+		statements = append(statements, single)
+	}
+
+	conflicts := []string{}
+	expected := oidcProvider + ":sub"
+	for _, st := range statements {
+		if st.Effect != "Allow" {
+			continue
+		}
+		for _, conditions := range st.Condition {
+			for key, value := range conditions {
+				if !strings.HasSuffix(key, ":sub") {
+					continue
+				}
+				values := []string{}
+				switch v := value.(type) {
+				case string:
+					values = append(values, v)
+				case []any:
+					for _, e := range v {
+						if s, ok := e.(string); ok {
+							values = append(values, s)
+						}
+					}
+				}
+				for _, v := range values {
+					if key != expected || v != subject {
+						conflicts = append(conflicts, strings.TrimSuffix(key, ":sub")+":"+v)
+					}
+				}
+			}
+		}
+	}
+	sort.Strings(conflicts)
+	return conflicts, nil
+}
+
 func updateRoleTrustPolicies(ctx context.Context, cfg aws.Config, dp awsconfig.AWSDataplane) (d diag.Diagnostics) {
 	clusterConfig, diags := dp.ClusterConfigurationData(ctx)
 	d.Append(diags...)
@@ -70,26 +208,32 @@ func updateRoleTrustPolicies(ctx context.Context, cfg aws.Config, dp awsconfig.A
 		return
 	}
 
-	cluster, err := util.DescribeKubeCluster(ctx, dp, cfg)
-	if err != nil {
-		d.AddError("failed to describe EKS cluster", err.Error())
+	cluster, diags := util.DescribeKubeCluster(ctx, dp, cfg)
+	d.Append(diags...)
+	if d.HasError() {
 		return
 	}
 
-	issArr := strings.Split(ptr.Deref(cluster.Identity.Oidc.Issuer, ""), "/")
+	issuer, diags := util.OidcIssuer(cluster)
+	d.Append(diags...)
+	if d.HasError() {
+		return
+	}
+	issArr := strings.Split(issuer, "/")
 	issuerID := issArr[len(issArr)-1]
 
-	d.Append(updateRoleTrustPolicy(ctx, cfg, clusterConfig, issuerID, clusterConfig.DpManagerRoleArn.ValueString(), "dp-manager", "deltastream")...)
+	namespaces := clusterConfig.Namespaces()
+	d.Append(updateRoleTrustPolicy(ctx, cfg, clusterConfig, issuerID, clusterConfig.DpManagerRoleArn.ValueString(), "dp-manager", namespaces.DeltaStream)...)
 	if d.HasError() {
 		return
 	}
 
-	d.Append(updateRoleTrustPolicy(ctx, cfg, clusterConfig, issuerID, clusterConfig.StoreProxyRoleArn.ValueString(), "store-proxy", "deltastream")...)
+	d.Append(updateRoleTrustPolicy(ctx, cfg, clusterConfig, issuerID, clusterConfig.StoreProxyRoleArn.ValueString(), "store-proxy", namespaces.DeltaStream)...)
 	if d.HasError() {
 		return
 	}
 
-	d.Append(updateRoleTrustPolicy(ctx, cfg, clusterConfig, issuerID, clusterConfig.WorkloadManagerRoleArn.ValueString(), "dp-operator-sa", "dp-operator")...)
+	d.Append(updateRoleTrustPolicy(ctx, cfg, clusterConfig, issuerID, clusterConfig.WorkloadManagerRoleArn.ValueString(), "dp-operator-sa", namespaces.DpOperator)...)
 	if d.HasError() {
 		return
 	}
@@ -0,0 +1,85 @@
+// Copyright (c) DeltaStream, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package aws
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestConflictingTrustSubjects(t *testing.T) {
+	const provider = "oidc.eks.us-west-2.amazonaws.com/id/ABC"
+	const subject = "system:serviceaccount:deltastream:dp-manager"
+
+	tests := []struct {
+		name    string
+		policy  string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:   "same subject",
+			policy: `{"Statement":[{"Effect":"Allow","Condition":{"StringEquals":{"oidc.eks.us-west-2.amazonaws.com/id/ABC:aud":"sts.amazonaws.com","oidc.eks.us-west-2.amazonaws.com/id/ABC:sub":"system:serviceaccount:deltastream:dp-manager"}}}]}`,
+			want:   []string{},
+		},
+		{
+			name:   "other cluster",
+			policy: `{"Statement":{"Effect":"Allow","Condition":{"StringEquals":{"oidc.eks.us-west-2.amazonaws.com/id/XYZ:sub":"system:serviceaccount:deltastream:dp-manager"}}}}`,
+			want:   []string{"oidc.eks.us-west-2.amazonaws.com/id/XYZ:system:serviceaccount:deltastream:dp-manager"},
+		},
+		{
+			name:   "other service accounts",
+			policy: `{"Statement":[{"Effect":"Allow","Condition":{"StringLike":{"oidc.eks.us-west-2.amazonaws.com/id/ABC:sub":["system:serviceaccount:deltastream:dp-manager","system:serviceaccount:other:*"]}}}]}`,
+			want:   []string{"oidc.eks.us-west-2.amazonaws.com/id/ABC:system:serviceaccount:other:*"},
+		},
+		{
+			name:   "no web identity",
+			policy: `{"Statement":[{"Effect":"Allow","Principal":{"Service":"ec2.amazonaws.com"},"Action":"sts:AssumeRole"}]}`,
+			want:   []string{},
+		},
+		{
+			name:    "invalid",
+			policy:  `{"Statement":`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := conflictingTrustSubjects(url.QueryEscape(tt.policy), provider, subject)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("conflictingTrustSubjects() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("conflictingTrustSubjects() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTrustPoliciesEqual(t *testing.T) {
+	desired := `{
+    "Version": "2012-10-17",
+    "Statement": [{"Effect": "Allow", "Action": "sts:AssumeRoleWithWebIdentity"}]
+}`
+	tests := []struct {
+		name    string
+		current string
+		want    bool
+	}{
+		{name: "reformatted", current: url.QueryEscape(`{"Statement":[{"Action":"sts:AssumeRoleWithWebIdentity","Effect":"Allow"}],"Version":"2012-10-17"}`), want: true},
+		{name: "different", current: url.QueryEscape(`{"Statement":[{"Action":"sts:AssumeRole","Effect":"Allow"}],"Version":"2012-10-17"}`), want: false},
+		{name: "invalid", current: url.QueryEscape(`{`), want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := trustPoliciesEqual(tt.current, desired); got != tt.want {
+				t.Errorf("trustPoliciesEqual() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright (c) DeltaStream, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package aws
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+
+	awsconfig "github.com/deltastreaminc/terraform-provider-dataplane/internal/deltastream/aws/config"
+)
+
+// renamedAttributesV0 maps attribute names used by v0 state to their current names, keyed by the path of the object
+// holding the attribute.
+var renamedAttributesV0 = map[string]map[string]string{
+	"configuration": {"karpenter_role_name": "karpenter_node_role_name"},
+	"status":        {"updated_at": "last_modified"},
+}
+
+// UpgradeState implements resource.ResourceWithUpgradeState.
+func (d *AWSDataplaneResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		0: {
+			StateUpgrader: func(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+				if req.RawState == nil {
+					resp.Diagnostics.AddError("Unable to upgrade state", "prior state is missing")
+					return
+				}
+
+				schemaType := awsconfig.Schema.Type().TerraformType(ctx)
+				upgraded, err := upgradeStateV0(req.RawState.JSON, schemaType)
+				if err != nil {
+					resp.Diagnostics.AddError("Unable to upgrade state", err.Error())
+					return
+				}
+				resp.DynamicValue = &tfprotov6.DynamicValue{JSON: upgraded}
+			},
+		},
+	}
+}
+
+// upgradeStateV0 renames attributes that changed name and drops attributes that are no longer part of the schema.
+// Attributes added since v0 are left out and decoded as null.
+func upgradeStateV0(raw []byte, schemaType tftypes.Type) ([]byte, error) {
+	state := map[string]any{}
+	if err := json.Unmarshal(raw, &state); err != nil {
+		return nil, fmt.Errorf("unable to decode prior state: %w", err)
+	}
+
+	for objectName, renames := range renamedAttributesV0 {
+		object, ok := state[objectName].(map[string]any)
+		if !ok {
+			continue
+		}
+		for oldName, newName := range renames {
+			if v, ok := object[oldName]; ok {
+				if _, exists := object[newName]; !exists {
+					object[newName] = v
+				}
+				delete(object, oldName)
+			}
+		}
+	}
+
+	pruneUnknownAttributes(state, schemaType)
+	return json.Marshal(state)
+}
+
+func pruneUnknownAttributes(value map[string]any, typ tftypes.Type) {
+	objectType, ok := typ.(tftypes.Object)
+	if !ok {
+		return
+	}
+	for k, v := range value {
+		attrType, ok := objectType.AttributeTypes[k]
+		if !ok {
+			delete(value, k)
+			continue
+		}
+		if nested, ok := v.(map[string]any); ok {
+			pruneUnknownAttributes(nested, attrType)
+		}
+	}
+}
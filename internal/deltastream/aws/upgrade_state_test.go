@@ -0,0 +1,68 @@
+// Copyright (c) DeltaStream, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package aws
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+
+	awsconfig "github.com/deltastreaminc/terraform-provider-dataplane/internal/deltastream/aws/config"
+)
+
+const stateV0 = `{
+  "assume_role": {"role_arn": "arn:aws:iam::123456789012:role/dp", "session_name": "dp", "region": "us-west-2"},
+  "configuration": {
+    "stack": "prod",
+    "account_id": "123456789012",
+    "infra_id": "abc123",
+    "eks_resource_id": "eks0",
+    "cluster_index": 0,
+    "product_version": "1.2.3",
+    "karpenter_role_name": "karpenter-node",
+    "removed_attribute": "value"
+  },
+  "status": {"provider_version": "0.1.0", "product_version": "1.2.3", "updated_at": "2024-01-01T00:00:00Z"}
+}`
+
+func TestUpgradeStateV0(t *testing.T) {
+	schemaType := awsconfig.Schema.Type().TerraformType(context.Background())
+
+	upgraded, err := upgradeStateV0([]byte(stateV0), schemaType)
+	if err != nil {
+		t.Fatalf("upgradeStateV0() error = %v", err)
+	}
+
+	value, err := tftypes.ValueFromJSON(upgraded, schemaType)
+	if err != nil {
+		t.Fatalf("upgraded state does not match schema: %v", err)
+	}
+
+	state := map[string]tftypes.Value{}
+	if err := value.As(&state); err != nil {
+		t.Fatalf("unable to read upgraded state: %v", err)
+	}
+
+	configuration := map[string]tftypes.Value{}
+	if err := state["configuration"].As(&configuration); err != nil {
+		t.Fatalf("unable to read configuration: %v", err)
+	}
+	var karpenterNodeRoleName string
+	if err := configuration["karpenter_node_role_name"].As(&karpenterNodeRoleName); err != nil || karpenterNodeRoleName != "karpenter-node" {
+		t.Errorf("karpenter_node_role_name = %q, %v, want karpenter-node", karpenterNodeRoleName, err)
+	}
+	if !configuration["kafka_auth_mode"].IsNull() {
+		t.Errorf("kafka_auth_mode = %v, want null", configuration["kafka_auth_mode"])
+	}
+
+	status := map[string]tftypes.Value{}
+	if err := state["status"].As(&status); err != nil {
+		t.Fatalf("unable to read status: %v", err)
+	}
+	var lastModified string
+	if err := status["last_modified"].As(&lastModified); err != nil || lastModified != "2024-01-01T00:00:00Z" {
+		t.Errorf("last_modified = %q, %v, want 2024-01-01T00:00:00Z", lastModified, err)
+	}
+}
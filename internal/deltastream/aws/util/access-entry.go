@@ -0,0 +1,81 @@
+// Copyright (c) DeltaStream, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package util
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+	"github.com/aws/aws-sdk-go-v2/service/eks/types"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// ClusterAdminAccessPolicyArn is the EKS access policy associated with the provider's access entry.
+const ClusterAdminAccessPolicyArn = "arn:aws:eks::aws:cluster-access-policy/AmazonEKSClusterAdminPolicy"
+
+// AccessEntryHint is appended to kube API authorization errors.
+const AccessEntryHint = "The IAM principal used by the provider must be mapped in the EKS cluster access configuration. " +
+	"Create an EKS access entry with the AmazonEKSClusterAdminPolicy access policy for it, or set configuration.manage_access_entry = true to let the provider create one."
+
+// EnsureAccessEntry makes sure the principal has an EKS access entry on the cluster with cluster admin access. When
+// principalArn is empty the IAM role of the caller is used. The cluster must use the API or API_AND_CONFIG_MAP
+// authentication mode.
+func EnsureAccessEntry(ctx context.Context, cfg aws.Config, clusterName, principalArn string) error {
+	if principalArn == "" {
+		callerIdentity, err := sts.NewFromConfig(cfg).GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+		if err != nil {
+			return fmt.Errorf("failed to get caller identity: %w", err)
+		}
+		principalArn, err = iamRoleArn(aws.ToString(callerIdentity.Arn))
+		if err != nil {
+			return err
+		}
+	}
+
+	eksClient := eks.NewFromConfig(cfg)
+	_, err := eksClient.CreateAccessEntry(ctx, &eks.CreateAccessEntryInput{
+		ClusterName:  aws.String(clusterName),
+		PrincipalArn: aws.String(principalArn),
+	})
+	var resourceInUseException *types.ResourceInUseException
+	switch {
+	case err == nil:
+		tflog.Info(ctx, "created EKS access entry", map[string]any{"cluster": clusterName, "principal": principalArn})
+	case errors.As(err, &resourceInUseException):
+		tflog.Debug(ctx, "EKS access entry already exists", map[string]any{"cluster": clusterName, "principal": principalArn})
+	default:
+		return fmt.Errorf("failed to create EKS access entry for %s: %w", principalArn, err)
+	}
+
+	if _, err := eksClient.AssociateAccessPolicy(ctx, &eks.AssociateAccessPolicyInput{
+		ClusterName:  aws.String(clusterName),
+		PrincipalArn: aws.String(principalArn),
+		PolicyArn:    aws.String(ClusterAdminAccessPolicyArn),
+		AccessScope:  &types.AccessScope{Type: types.AccessScopeTypeCluster},
+	}); err != nil {
+		return fmt.Errorf("failed to associate EKS access policy with %s: %w", principalArn, err)
+	}
+	return nil
+}
+
+// iamRoleArn converts an STS assumed role ARN to the ARN of the IAM role. Other ARNs are returned unchanged.
+func iamRoleArn(arn string) (string, error) {
+	parts := strings.SplitN(arn, ":", 6)
+	if len(parts) != 6 {
+		return "", fmt.Errorf("invalid caller ARN %s", arn)
+	}
+	if parts[2] != "sts" || !strings.HasPrefix(parts[5], "assumed-role/") {
+		return arn, nil
+	}
+	resource := strings.Split(parts[5], "/")
+	if len(resource) < 3 {
+		return "", fmt.Errorf("invalid assumed role ARN %s", arn)
+	}
+	return fmt.Sprintf("%s:%s:iam::%s:role/%s", parts[0], parts[1], parts[4], resource[1]), nil
+}
@@ -0,0 +1,31 @@
+// Copyright (c) DeltaStream, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package util
+
+import "testing"
+
+func TestIamRoleArn(t *testing.T) {
+	tests := []struct {
+		arn     string
+		want    string
+		wantErr bool
+	}{
+		{arn: "arn:aws:sts::123456789012:assumed-role/dp-infra/session", want: "arn:aws:iam::123456789012:role/dp-infra"},
+		{arn: "arn:aws-us-gov:sts::123456789012:assumed-role/dp-infra/session", want: "arn:aws-us-gov:iam::123456789012:role/dp-infra"},
+		{arn: "arn:aws:iam::123456789012:user/admin", want: "arn:aws:iam::123456789012:user/admin"},
+		{arn: "arn:aws:sts::123456789012:assumed-role/dp-infra", wantErr: true},
+		{arn: "invalid", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.arn, func(t *testing.T) {
+			got, err := iamRoleArn(tt.arn)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("iamRoleArn(%q) error = %v, wantErr %v", tt.arn, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("iamRoleArn(%q) = %q, want %q", tt.arn, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+// Copyright (c) DeltaStream, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package util
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+)
+
+const (
+	DefaultAwsMaxAttempts    = 10
+	DefaultAwsRequestTimeout = time.Minute
+)
+
+// newAwsHttpClient returns the HTTP client of the AWS SDK. The request timeout bounds the wait for the response
+// headers of an attempt rather than the whole request, so that large object transfers are not cut off on slow links.
+func (s ClientSettings) newAwsHttpClient() *awshttp.BuildableClient {
+	requestTimeout := DefaultAwsRequestTimeout
+	if s.AwsRequestTimeout > 0 {
+		requestTimeout = s.AwsRequestTimeout
+	}
+	return awshttp.NewBuildableClient().WithTransportOptions(func(tr *http.Transport) {
+		tr.Proxy = s.proxyFunc()
+		tr.ResponseHeaderTimeout = requestTimeout
+	})
+}
+
+// newAwsRetryer returns a retryer that backs off and rate limits client side when AWS throttles requests.
+func (s ClientSettings) newAwsRetryer() aws.Retryer {
+	maxAttempts := DefaultAwsMaxAttempts
+	if s.AwsMaxAttempts > 0 {
+		maxAttempts = s.AwsMaxAttempts
+	}
+	return retry.NewAdaptiveMode(func(o *retry.AdaptiveModeOptions) {
+		o.StandardOptions = append(o.StandardOptions, func(so *retry.StandardOptions) {
+			so.MaxAttempts = maxAttempts
+		})
+	})
+}
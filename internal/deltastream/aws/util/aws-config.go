@@ -6,25 +6,65 @@ package util
 import (
 	"context"
 	"fmt"
+	"regexp"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
 	"github.com/aws/aws-sdk-go-v2/service/sts"
+	ststypes "github.com/aws/aws-sdk-go-v2/service/sts/types"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
 
 	awsconfig "github.com/deltastreaminc/terraform-provider-dataplane/internal/deltastream/aws/config"
 )
 
-func GetAwsConfig(ctx context.Context, dp awsconfig.AWSDataplane) (cfg aws.Config, d diag.Diagnostics) {
+// sessionNamePrefix prefixes the default role session name so that CloudTrail events can be attributed to a dataplane.
+const sessionNamePrefix = "deltastream-dp-"
+
+// maxSessionNameLength is the STS limit on the length of a role session name.
+const maxSessionNameLength = 64
+
+var invalidSessionNameChars = regexp.MustCompile(`[^\w+=,.@-]`)
+
+// DefaultSessionName returns the role session name used when session_name is not set. Characters STS does not accept
+// are replaced and the name is truncated to the STS length limit.
+func DefaultSessionName(infraId string) string {
+	name := sessionNamePrefix + invalidSessionNameChars.ReplaceAllString(infraId, "-")
+	if len(name) > maxSessionNameLength {
+		name = name[:maxSessionNameLength]
+	}
+	return name
+}
+
+func GetAwsConfig(ctx context.Context, settings ClientSettings, dp awsconfig.AWSDataplane) (cfg aws.Config, d diag.Diagnostics) {
 	assumeRoleData, diags := dp.AssumeRoleData(ctx)
 	d.Append(diags...)
 	if d.HasError() {
 		return
 	}
 
-	cfgOpts := config.WithClientLogMode(aws.LogDeprecatedUsage)
-	cfg, err := config.LoadDefaultConfig(ctx, cfgOpts)
+	if (assumeRoleData.SessionName.IsNull() || assumeRoleData.SessionName.IsUnknown()) && !(dp.ClusterConfiguration.IsNull() || dp.ClusterConfiguration.IsUnknown()) {
+		clusterConfig, diags := dp.ClusterConfigurationData(ctx)
+		d.Append(diags...)
+		if d.HasError() {
+			return
+		}
+		if infraId := clusterConfig.InfraId.ValueString(); infraId != "" {
+			assumeRoleData.SessionName = basetypes.NewStringValue(DefaultSessionName(infraId))
+		}
+	}
+
+	return GetAwsConfigForAssumeRole(ctx, settings, assumeRoleData)
+}
+
+func GetAwsConfigForAssumeRole(ctx context.Context, settings ClientSettings, assumeRoleData awsconfig.AssumeRole) (cfg aws.Config, d diag.Diagnostics) {
+	cfgOpts := []func(*config.LoadOptions) error{
+		config.WithClientLogMode(aws.LogDeprecatedUsage),
+		config.WithHTTPClient(settings.newAwsHttpClient()),
+		config.WithRetryer(settings.newAwsRetryer),
+	}
+	cfg, err := config.LoadDefaultConfig(ctx, cfgOpts...)
 	if err != nil {
 		d.AddError("Failed to load AWS SDK config", err.Error())
 		return
@@ -33,18 +73,76 @@ func GetAwsConfig(ctx context.Context, dp awsconfig.AWSDataplane) (cfg aws.Confi
 		cfg.Region = assumeRoleData.Region.ValueString()
 	}
 
-	stsClient := sts.NewFromConfig(cfg)
-	creds := stscreds.NewAssumeRoleProvider(stsClient, assumeRoleData.RoleArn.ValueString(), func(o *stscreds.AssumeRoleOptions) {
-		if !assumeRoleData.SessionName.IsUnknown() && !assumeRoleData.SessionName.IsNull() {
-			o.RoleSessionName = assumeRoleData.SessionName.ValueString()
+	sessionTags := []ststypes.Tag{}
+	if !assumeRoleData.SessionTags.IsUnknown() && !assumeRoleData.SessionTags.IsNull() {
+		tags := map[string]string{}
+		d.Append(assumeRoleData.SessionTags.ElementsAs(ctx, &tags, false)...)
+		if d.HasError() {
+			return
+		}
+		for _, k := range SortedTagKeys(tags) {
+			sessionTags = append(sessionTags, ststypes.Tag{Key: aws.String(k), Value: aws.String(tags[k])})
 		}
-	})
-	cfg.Credentials = creds
+	}
+
+	roleArns := []string{}
+	if !assumeRoleData.ViaRoleArns.IsUnknown() && !assumeRoleData.ViaRoleArns.IsNull() {
+		d.Append(assumeRoleData.ViaRoleArns.ElementsAs(ctx, &roleArns, false)...)
+		if d.HasError() {
+			return
+		}
+	}
+	roleArns = append(roleArns, assumeRoleData.RoleArn.ValueString())
+
+	// Each hop assumes the next role with the credentials of the previous one. The source identity is set on the first
+	// hop and carried along the chain by STS, session tags are only passed to the final role.
+	for i, roleArn := range roleArns {
+		first, last := i == 0, i == len(roleArns)-1
+		stsClient := sts.NewFromConfig(cfg)
+		cfg.Credentials = aws.NewCredentialsCache(stscreds.NewAssumeRoleProvider(stsClient, roleArn, func(o *stscreds.AssumeRoleOptions) {
+			if !assumeRoleData.SessionName.IsUnknown() && !assumeRoleData.SessionName.IsNull() {
+				o.RoleSessionName = assumeRoleData.SessionName.ValueString()
+			}
+			if first && !assumeRoleData.SourceIdentity.IsUnknown() && !assumeRoleData.SourceIdentity.IsNull() {
+				o.SourceIdentity = aws.String(assumeRoleData.SourceIdentity.ValueString())
+			}
+			if last {
+				o.Tags = sessionTags
+			}
+		}))
+	}
 	return cfg, d
 }
 
+// DsRegion returns the resolved ds_region, falling back to the region of the AWS
+// config when neither ds_region nor assume_role.region is set.
+func DsRegion(cfg aws.Config, cc awsconfig.ClusterConfiguration) string {
+	if cc.DsRegion.IsNull() || cc.DsRegion.IsUnknown() || cc.DsRegion.ValueString() == "" {
+		return cfg.Region
+	}
+	return cc.DsRegion.ValueString()
+}
+
+// SerdeBucketRegion returns serde_bucket_region, falling back to the resolved
+// ds_region.
+func SerdeBucketRegion(cfg aws.Config, cc awsconfig.ClusterConfiguration) string {
+	if cc.SerdeBucketRegion.IsNull() || cc.SerdeBucketRegion.IsUnknown() || cc.SerdeBucketRegion.ValueString() == "" {
+		return DsRegion(cfg, cc)
+	}
+	return cc.SerdeBucketRegion.ValueString()
+}
+
+// O11yBucketRegion returns o11y_bucket_region, falling back to the region of the
+// resolved AWS config.
+func O11yBucketRegion(cfg aws.Config, cc awsconfig.ClusterConfiguration) string {
+	if cc.O11yBucketRegion.IsNull() || cc.O11yBucketRegion.IsUnknown() || cc.O11yBucketRegion.ValueString() == "" {
+		return cfg.Region
+	}
+	return cc.O11yBucketRegion.ValueString()
+}
+
 func GetARNForCPService(ctx context.Context, cfg aws.Config, cc awsconfig.ClusterConfiguration, service string) string {
-	return fmt.Sprintf("arn:aws:%s:%s:%s", service, cc.DsRegion.ValueString(), cc.DsAccountId.ValueString())
+	return fmt.Sprintf("arn:aws:%s:%s:%s", service, DsRegion(cfg, cc), cc.DsAccountId.ValueString())
 }
 
 func GetARNForService(ctx context.Context, cfg aws.Config, cc awsconfig.ClusterConfiguration, service string) string {
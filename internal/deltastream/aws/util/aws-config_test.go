@@ -0,0 +1,28 @@
+// Copyright (c) DeltaStream, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package util
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDefaultSessionName(t *testing.T) {
+	tests := []struct {
+		name    string
+		infraId string
+		want    string
+	}{
+		{name: "plain infra id", infraId: "a1b2c3", want: "deltastream-dp-a1b2c3"},
+		{name: "invalid characters", infraId: "a1/b2 c3", want: "deltastream-dp-a1-b2-c3"},
+		{name: "truncated", infraId: strings.Repeat("x", 80), want: "deltastream-dp-" + strings.Repeat("x", 64-len("deltastream-dp-"))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DefaultSessionName(tt.infraId); got != tt.want {
+				t.Errorf("DefaultSessionName(%q) = %q, want %q", tt.infraId, got, tt.want)
+			}
+		})
+	}
+}
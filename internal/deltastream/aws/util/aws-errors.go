@@ -0,0 +1,74 @@
+// Copyright (c) DeltaStream, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package util
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/smithy-go"
+)
+
+// Remediation names what a user should check when an AWS request fails: the IAM action the provider needs and the
+// configuration attribute that identifies the resource. Either may be empty.
+type Remediation struct {
+	Action    string
+	Attribute string
+}
+
+var accessDeniedErrorCodes = map[string]struct{}{
+	"AccessDenied":          {},
+	"AccessDeniedException": {},
+	"AuthorizationError":    {},
+	"Forbidden":             {},
+	"UnauthorizedOperation": {},
+	"UnauthorizedException": {},
+}
+
+var notFoundErrorCodes = map[string]struct{}{
+	"AWS.SimpleQueueService.NonExistentQueue": {},
+	"NoSuchBucket":                {},
+	"NoSuchEntity":                {},
+	"NoSuchKey":                   {},
+	"NotFound":                    {},
+	"NotFoundException":           {},
+	"QueueDoesNotExist":           {},
+	"RepositoryNotFoundException": {},
+	"ResourceNotFoundException":   {},
+}
+
+// AwsErrorDetail returns the detail for a diagnostic reporting a failed AWS request. Access denied, not found and
+// throttling errors are followed by a hint naming the permission or attribute to check.
+func AwsErrorDetail(err error, r Remediation) string {
+	if hint := remediationHint(err, r); hint != "" {
+		return err.Error() + "\n\n" + hint
+	}
+	return err.Error()
+}
+
+func remediationHint(err error, r Remediation) string {
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) {
+		return ""
+	}
+
+	code := apiErr.ErrorCode()
+	if _, ok := accessDeniedErrorCodes[code]; ok {
+		if r.Action == "" {
+			return "The IAM principal used by the provider is not authorized to make this request, check the policies attached to it."
+		}
+		return fmt.Sprintf("The IAM principal used by the provider is likely missing the %s permission, check the policies attached to it and any resource policy.", r.Action)
+	}
+	if _, ok := notFoundErrorCodes[code]; ok {
+		if r.Attribute == "" {
+			return "The resource does not exist, check that it was created in the expected account and region."
+		}
+		return fmt.Sprintf("The resource does not exist, check that configuration.%s names a resource in the expected account and region.", r.Attribute)
+	}
+	if _, ok := retry.DefaultThrottleErrorCodes[code]; ok {
+		return "The request was throttled by AWS. Retry the operation, or reduce the number of concurrent requests in this account and region."
+	}
+	return ""
+}
@@ -0,0 +1,46 @@
+// Copyright (c) DeltaStream, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package util
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/aws/smithy-go"
+)
+
+func TestAwsErrorDetail(t *testing.T) {
+	remediation := Remediation{Action: "s3:GetObject", Attribute: "product_artifacts_bucket"}
+	tests := []struct {
+		name     string
+		err      error
+		wantHint string
+	}{
+		{name: "access denied", err: &smithy.GenericAPIError{Code: "AccessDenied"}, wantHint: "s3:GetObject"},
+		{name: "wrapped access denied", err: fmt.Errorf("failed: %w", &smithy.GenericAPIError{Code: "AccessDeniedException"}), wantHint: "s3:GetObject"},
+		{name: "not found", err: &smithy.GenericAPIError{Code: "NoSuchBucket"}, wantHint: "configuration.product_artifacts_bucket"},
+		{name: "throttled", err: &smithy.GenericAPIError{Code: "ThrottlingException"}, wantHint: "throttled"},
+		{name: "other api error", err: &smithy.GenericAPIError{Code: "ValidationException"}},
+		{name: "not an api error", err: errors.New("connection refused")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := AwsErrorDetail(tt.err, remediation)
+			if !strings.HasPrefix(got, tt.err.Error()) {
+				t.Errorf("AwsErrorDetail() = %q, want the error message first", got)
+			}
+			if tt.wantHint == "" {
+				if got != tt.err.Error() {
+					t.Errorf("AwsErrorDetail() = %q, want no hint", got)
+				}
+				return
+			}
+			if !strings.Contains(got, tt.wantHint) {
+				t.Errorf("AwsErrorDetail() = %q, want hint containing %q", got, tt.wantHint)
+			}
+		})
+	}
+}
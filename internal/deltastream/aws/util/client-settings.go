@@ -0,0 +1,36 @@
+// Copyright (c) DeltaStream, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package util
+
+import (
+	"time"
+
+	"golang.org/x/net/http/httpproxy"
+)
+
+// ClientSettings holds the provider level settings of the AWS and kube clients. Every resource and data source
+// carries its own copy, so that provider aliases configured with different settings do not affect each other.
+type ClientSettings struct {
+	// Proxy is the proxy used by AWS and kube clients. When neither HTTPProxy nor HTTPSProxy is set the clients fall
+	// back to the proxy environment variables.
+	Proxy httpproxy.Config
+
+	// AwsMaxAttempts is the maximum number of attempts of an AWS API request. Zero selects the default.
+	AwsMaxAttempts int
+	// AwsRequestTimeout bounds the wait for the response of an AWS API request attempt. Zero selects the default.
+	AwsRequestTimeout time.Duration
+
+	// KubeMaxRetries is the number of retries of in-cluster operations during install and destroy. Zero selects the
+	// default.
+	KubeMaxRetries int
+	// KubeMaxBackoff caps the delay between retries of in-cluster operations. Zero selects the default.
+	KubeMaxBackoff time.Duration
+	// KubeQPS is the client side rate limit of requests to the kube API server. Zero selects the default.
+	KubeQPS float64
+	// KubeBurst is the burst allowed above KubeQPS. Zero selects the default.
+	KubeBurst int
+
+	// DryRun applies manifests with server side dry run, nothing is written to AWS or the cluster.
+	DryRun bool
+}
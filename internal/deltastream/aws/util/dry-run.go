@@ -0,0 +1,90 @@
+// Copyright (c) DeltaStream, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package util
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// dryRunApplyObjects applies the objects with server side dry run and reports the objects that would be created or
+// updated as a warning.
+func dryRunApplyObjects(ctx context.Context, kubeClient *RetryableClient, name string, objs []*unstructured.Unstructured) (d diag.Diagnostics) {
+	created, updated := []string{}, []string{}
+	unchanged := 0
+	for _, u := range objs {
+		ref := objectRef(u)
+		existing := u.DeepCopy()
+		if err := kubeClient.Client.Get(ctx, client.ObjectKeyFromObject(u), existing); err != nil {
+			if meta.IsNoMatchError(err) {
+				// the CRD is installed by an earlier manifest, the object can not be validated before it is
+				created = append(created, ref+" (kind not installed yet)")
+				continue
+			}
+			if !k8serrors.IsNotFound(err) {
+				d.AddError("dry run: unable to read "+ref, err.Error())
+				continue
+			}
+			existing = nil
+		}
+
+		result := u.DeepCopy()
+		if err := kubeClient.Client.Patch(ctx, result, client.Apply, client.FieldOwner(FieldManager), client.ForceOwnership, client.DryRunAll); err != nil {
+			d.AddError("dry run: "+ref+" would be rejected", err.Error())
+			continue
+		}
+
+		switch {
+		case existing == nil:
+			created = append(created, ref)
+		case !equality.Semantic.DeepEqual(comparableObject(existing), comparableObject(result)):
+			updated = append(updated, ref)
+		default:
+			unchanged++
+		}
+	}
+
+	tflog.Info(ctx, "dry run of "+name, map[string]any{"create": created, "update": updated, "unchanged": unchanged})
+	if len(created) > 0 || len(updated) > 0 {
+		d.AddWarning(fmt.Sprintf("dry run: %s would create %d and update %d objects", name, len(created), len(updated)), changeList(created, updated))
+	}
+	return
+}
+
+// comparableObject strips the fields of an object that change on every write.
+func comparableObject(u *unstructured.Unstructured) map[string]any {
+	obj := u.DeepCopy().Object
+	unstructured.RemoveNestedField(obj, "metadata", "resourceVersion")
+	unstructured.RemoveNestedField(obj, "metadata", "generation")
+	unstructured.RemoveNestedField(obj, "metadata", "managedFields")
+	unstructured.RemoveNestedField(obj, "status")
+	return obj
+}
+
+func objectRef(u *unstructured.Unstructured) string {
+	if u.GetNamespace() == "" {
+		return u.GetKind() + " " + u.GetName()
+	}
+	return u.GetKind() + " " + u.GetNamespace() + "/" + u.GetName()
+}
+
+func changeList(created, updated []string) string {
+	lines := []string{}
+	for _, ref := range created {
+		lines = append(lines, "+ "+ref)
+	}
+	for _, ref := range updated {
+		lines = append(lines, "~ "+ref)
+	}
+	return strings.Join(lines, "\n")
+}
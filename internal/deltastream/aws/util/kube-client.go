@@ -7,6 +7,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -28,9 +29,11 @@ import (
 	sourcev1 "github.com/fluxcd/source-controller/api/v1"
 	sourcev1b2 "github.com/fluxcd/source-controller/api/v1beta2"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/jellydator/ttlcache/v3"
 	"github.com/sethvargo/go-retry"
+	corev1 "k8s.io/api/core/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -51,6 +54,12 @@ clusters:
 - cluster:
     server: {{ .Endpoint }}
     certificate-authority-data: {{ .CAData }}
+{{- if .TLSServerName }}
+    tls-server-name: {{ .TLSServerName }}
+{{- end }}
+{{- if .ProxyURL }}
+    proxy-url: {{ .ProxyURL }}
+{{- end }}
   name: kubernetes
 contexts:
 - context:
@@ -100,21 +109,97 @@ func getKubernetesAuthToken(ctx context.Context, cfg aws.Config, k8sClusterName
 	return "k8s-aws-v1." + base64.RawURLEncoding.EncodeToString([]byte(presignedURL.String())), nil
 }
 
-func GetKubeClusterName(ctx context.Context, dp awsconfig.AWSDataplane) (name string, err error) {
+func GetKubeClusterName(ctx context.Context, dp awsconfig.AWSDataplane) (name string, d diag.Diagnostics) {
 	clusterConfigurationData, diags := dp.ClusterConfigurationData(ctx)
-	if diags.HasError() {
-		return "", fmt.Errorf("failed to get cluster configuration data: %v", diags.Errors())
+	d.Append(diags...)
+	if d.HasError() {
+		return
 	}
 
-	return fmt.Sprintf("dp-%s-%s-%s-%d", clusterConfigurationData.InfraId.ValueString(), clusterConfigurationData.Stack.ValueString(), clusterConfigurationData.EksResourceId.ValueString(), ptr.Deref(clusterConfigurationData.ClusterIndex.ValueInt64Pointer(), 0)), nil
+	return KubeClusterName(clusterConfigurationData.InfraId.ValueString(), clusterConfigurationData.Stack.ValueString(), clusterConfigurationData.EksResourceId.ValueString(), ptr.Deref(clusterConfigurationData.ClusterIndex.ValueInt64Pointer(), 0)), d
+}
+
+func KubeClusterName(infraID, stack, resourceID string, index int64) string {
+	return fmt.Sprintf("dp-%s-%s-%s-%d", infraID, stack, resourceID, index)
 }
 
-func DescribeKubeCluster(ctx context.Context, dp awsconfig.AWSDataplane, cfg aws.Config) (cluster *types.Cluster, err error) {
-	clusterName, err := GetKubeClusterName(ctx, dp)
+func DescribeKubeCluster(ctx context.Context, dp awsconfig.AWSDataplane, cfg aws.Config) (cluster *types.Cluster, d diag.Diagnostics) {
+	clusterName, diags := GetKubeClusterName(ctx, dp)
+	d.Append(diags...)
+	if d.HasError() {
+		return
+	}
+
+	timeout, diags := clusterActiveTimeout(ctx, dp)
+	d.Append(diags...)
+	if d.HasError() {
+		return
+	}
+
+	cluster, err := WaitForKubeClusterActive(ctx, cfg, clusterName, timeout)
 	if err != nil {
-		return nil, err
+		d.Append(clusterErrorDiagnostic("error describing EKS cluster", err))
 	}
+	return
+}
+
+// ClusterNotActiveError is returned when the EKS cluster exists but is not ACTIVE.
+type ClusterNotActiveError struct {
+	Name   string
+	Status types.ClusterStatus
+}
+
+func (e *ClusterNotActiveError) Error() string {
+	return fmt.Sprintf("EKS cluster %s is %s, it must be ACTIVE", e.Name, e.Status)
+}
+
+// transient reports whether the cluster is expected to become ACTIVE without intervention.
+func (e *ClusterNotActiveError) transient() bool {
+	return e.Status == types.ClusterStatusCreating || e.Status == types.ClusterStatusUpdating || e.Status == types.ClusterStatusPending
+}
 
+// WaitForKubeClusterActive describes the named EKS cluster, waiting up to timeout for a cluster that is being created
+// or updated to become ACTIVE.
+func WaitForKubeClusterActive(ctx context.Context, cfg aws.Config, clusterName string, timeout time.Duration) (cluster *types.Cluster, err error) {
+	err = retry.Do(ctx, retry.WithMaxDuration(timeout, retry.NewConstant(time.Second*15)), func(ctx context.Context) error {
+		cluster, err = DescribeKubeClusterByName(ctx, cfg, clusterName)
+		var notActive *ClusterNotActiveError
+		if errors.As(err, &notActive) && notActive.transient() {
+			tflog.Info(ctx, "waiting for EKS cluster to become active", map[string]any{"cluster": clusterName, "status": string(notActive.Status)})
+			return retry.RetryableError(err)
+		}
+		return err
+	})
+	return cluster, err
+}
+
+func clusterActiveTimeout(ctx context.Context, dp awsconfig.AWSDataplane) (timeout time.Duration, d diag.Diagnostics) {
+	clusterConfigurationData, diags := dp.ClusterConfigurationData(ctx)
+	d.Append(diags...)
+	if d.HasError() {
+		return
+	}
+	timeout, err := time.ParseDuration(clusterConfigurationData.ClusterActiveTimeout.ValueString())
+	if err != nil {
+		d.AddError("invalid cluster active timeout", err.Error())
+	}
+	return
+}
+
+// clusterErrorDiagnostic names the cluster status when the cluster is not ACTIVE.
+func clusterErrorDiagnostic(summary string, err error) diag.Diagnostic {
+	var notActive *ClusterNotActiveError
+	if errors.As(err, &notActive) {
+		detail := err.Error()
+		if notActive.transient() {
+			detail += ", retry once the cluster is ACTIVE or set configuration.cluster_active_timeout to wait for it"
+		}
+		return diag.NewErrorDiagnostic("EKS cluster is not active", detail)
+	}
+	return diag.NewErrorDiagnostic(summary, AwsErrorDetail(err, Remediation{Action: "eks:DescribeCluster", Attribute: "eks_resource_id"}))
+}
+
+func DescribeKubeClusterByName(ctx context.Context, cfg aws.Config, clusterName string) (cluster *types.Cluster, err error) {
 	eksClient := eks.NewFromConfig(cfg)
 	ekcDescOut, err := eksClient.DescribeCluster(ctx, &eks.DescribeClusterInput{Name: aws.String(clusterName)})
 	if err != nil {
@@ -122,14 +207,49 @@ func DescribeKubeCluster(ctx context.Context, dp awsconfig.AWSDataplane, cfg aws
 	}
 
 	cluster = ekcDescOut.Cluster
+	if cluster != nil && cluster.Status != types.ClusterStatusActive {
+		return nil, &ClusterNotActiveError{Name: clusterName, Status: cluster.Status}
+	}
 	if cluster == nil || cluster.Endpoint == nil || cluster.CertificateAuthority == nil || cluster.CertificateAuthority.Data == nil {
 		return nil, fmt.Errorf("failed to get EKS cluster: cluster data is nil")
 	}
 	return cluster, nil
 }
 
-func GetKubeConfig(ctx context.Context, dp awsconfig.AWSDataplane, cfg aws.Config) (kubeConfig []byte, err error) {
-	cluster, err := DescribeKubeCluster(ctx, dp, cfg)
+// OidcIssuer returns the OIDC issuer URL of the cluster. Clusters that are still being created or have no OIDC
+// provider associated report an error instead.
+func OidcIssuer(cluster *types.Cluster) (issuer string, d diag.Diagnostics) {
+	if cluster.Identity == nil || cluster.Identity.Oidc == nil || aws.ToString(cluster.Identity.Oidc.Issuer) == "" {
+		d.AddError("cluster has no OIDC issuer configured", fmt.Sprintf("EKS cluster %s does not report an OIDC issuer, ensure the cluster is active and has an IAM OIDC provider associated", aws.ToString(cluster.Name)))
+		return
+	}
+	return *cluster.Identity.Oidc.Issuer, d
+}
+
+func GetKubeConfig(ctx context.Context, settings ClientSettings, dp awsconfig.AWSDataplane, cfg aws.Config) (kubeConfig []byte, d diag.Diagnostics) {
+	clusterName, diags := GetKubeClusterName(ctx, dp)
+	d.Append(diags...)
+	if d.HasError() {
+		return
+	}
+
+	clusterConfigurationData, diags := dp.ClusterConfigurationData(ctx)
+	d.Append(diags...)
+	if d.HasError() {
+		return
+	}
+
+	kubeConfig, err := GetKubeConfigForCluster(ctx, settings, cfg, clusterName, clusterConfigurationData.KubeApiEndpointOverride)
+	if err != nil {
+		d.AddError("error getting kubeconfig", AwsErrorDetail(err, Remediation{Action: "eks:DescribeCluster", Attribute: "eks_resource_id"}))
+	}
+	return
+}
+
+// GetKubeConfigForCluster renders a kubeconfig for the named EKS cluster. A non-null endpointOverride replaces the
+// cluster endpoint while the server certificate is still verified against the cluster hostname.
+func GetKubeConfigForCluster(ctx context.Context, settings ClientSettings, cfg aws.Config, clusterName string, endpointOverride basetypes.StringValue) (kubeConfig []byte, err error) {
+	cluster, err := DescribeKubeClusterByName(ctx, cfg, clusterName)
 	if err != nil {
 		return nil, err
 	}
@@ -144,11 +264,40 @@ func GetKubeConfig(ctx context.Context, dp awsconfig.AWSDataplane, cfg aws.Confi
 		return nil, fmt.Errorf("failed to get k8s token: %w", err)
 	}
 
+	endpoint, tlsServerName := *cluster.Endpoint, ""
+	if !(endpointOverride.IsNull() || endpointOverride.IsUnknown()) {
+		endpoint = endpointOverride.ValueString()
+		// keep verifying the server certificate against the cluster hostname
+		clusterEndpoint, err := url.Parse(*cluster.Endpoint)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse cluster endpoint: %w", err)
+		}
+		tlsServerName = clusterEndpoint.Hostname()
+		tflog.Debug(ctx, "overriding kube API endpoint", map[string]any{"cluster endpoint": *cluster.Endpoint, "endpoint": endpoint})
+	}
+
+	proxyURL := ""
+	if settings.hasProxy() {
+		endpointURL, err := url.Parse(endpoint)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse kube API endpoint: %w", err)
+		}
+		u, err := settings.proxyFunc()(&http.Request{URL: endpointURL})
+		if err != nil {
+			return nil, fmt.Errorf("failed to determine proxy for kube API endpoint: %w", err)
+		}
+		if u != nil {
+			proxyURL = u.String()
+		}
+	}
+
 	kubeConfigBuf := bytes.NewBuffer(nil)
 	err = t.Execute(kubeConfigBuf, map[string]string{
-		"Endpoint": *cluster.Endpoint,
-		"CAData":   *cluster.CertificateAuthority.Data,
-		"Token":    token,
+		"Endpoint":      endpoint,
+		"CAData":        *cluster.CertificateAuthority.Data,
+		"TLSServerName": tlsServerName,
+		"ProxyURL":      proxyURL,
+		"Token":         token,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute kubeconfig template: %w", err)
@@ -156,17 +305,94 @@ func GetKubeConfig(ctx context.Context, dp awsconfig.AWSDataplane, cfg aws.Confi
 	return kubeConfigBuf.Bytes(), nil
 }
 
-var kubeClientCache = ttlcache.New[string, *RetryableClient]()
+// kubeClientKey identifies a cached kube client. Clients are built from the client settings, so the clients of
+// provider aliases with different settings are cached separately.
+type kubeClientKey struct {
+	clusterName string
+	settings    ClientSettings
+}
+
+var kubeClientCache = ttlcache.New[kubeClientKey, *RetryableClient]()
 
-func GetKubeClient(ctx context.Context, cfg aws.Config, dp awsconfig.AWSDataplane) (rClient *RetryableClient, err error) {
+func GetKubeClient(ctx context.Context, settings ClientSettings, cfg aws.Config, dp awsconfig.AWSDataplane) (rClient *RetryableClient, d diag.Diagnostics) {
+	clusterName, diags := GetKubeClusterName(ctx, dp)
+	d.Append(diags...)
+	if d.HasError() {
+		return
+	}
+
+	clusterConfigurationData, diags := dp.ClusterConfigurationData(ctx)
+	d.Append(diags...)
+	if d.HasError() {
+		return
+	}
+
+	timeout, diags := clusterActiveTimeout(ctx, dp)
+	d.Append(diags...)
+	if d.HasError() {
+		return
+	}
+	cached := kubeClientCache.Has(kubeClientKey{clusterName: clusterName, settings: settings})
+	// without a timeout the cluster status is checked when the kubeconfig is rendered
+	if timeout > 0 && !cached {
+		if _, err := WaitForKubeClusterActive(ctx, cfg, clusterName, timeout); err != nil {
+			d.Append(clusterErrorDiagnostic("error getting kube client", err))
+			return
+		}
+	}
+
+	// a dry run does not write to AWS, without an existing access entry the client fails with the access entry hint
+	if clusterConfigurationData.ManageAccessEntry.ValueBool() && !cached && settings.DryRun {
+		tflog.Info(ctx, "dry run: not ensuring EKS access entry", map[string]any{"cluster": clusterName})
+	}
+	if clusterConfigurationData.ManageAccessEntry.ValueBool() && !cached && !settings.DryRun {
+		assumeRole, diags := dp.AssumeRoleData(ctx)
+		d.Append(diags...)
+		if d.HasError() {
+			return
+		}
+		if err := EnsureAccessEntry(ctx, cfg, clusterName, assumeRole.RoleArn.ValueString()); err != nil {
+			d.AddError("error ensuring EKS access entry", AwsErrorDetail(err, Remediation{Action: "eks:CreateAccessEntry"}))
+			return
+		}
+	}
+
+	rClient, err := GetKubeClientForCluster(ctx, settings, cfg, clusterName, clusterConfigurationData.KubeApiEndpointOverride)
+	if err != nil {
+		if errors.Is(err, ErrKubeUnauthorized) {
+			d.AddError("error getting kube client", err.Error()+"\n\n"+AccessEntryHint)
+			return
+		}
+		d.Append(clusterErrorDiagnostic("error getting kube client", err))
+	}
+	return
+}
+
+// ErrKubeUnauthorized is returned when the kube API rejects the provider's credentials.
+var ErrKubeUnauthorized = errors.New("kube API denied access")
+
+// DiagnosticsError summarizes error diagnostics as an error, for use where an error is required such as inside
+// retry.Do.
+func DiagnosticsError(d diag.Diagnostics) error {
+	msgs := []string{}
+	for _, e := range d.Errors() {
+		msgs = append(msgs, e.Summary()+": "+e.Detail())
+	}
+	return errors.New(strings.Join(msgs, "; "))
+}
+
+// GetKubeClientForCluster returns a client for the named EKS cluster. Clients are cached per cluster and client
+// settings.
+func GetKubeClientForCluster(ctx context.Context, settings ClientSettings, cfg aws.Config, clusterName string, endpointOverride basetypes.StringValue) (rClient *RetryableClient, err error) {
+	key := kubeClientKey{clusterName: clusterName, settings: settings}
 	kubeClientCache.DeleteExpired()
-	if v := kubeClientCache.Get("kubeClient"); v != nil {
+	if v := kubeClientCache.Get(key); v != nil {
 		tflog.Debug(ctx, "reusing kube client")
 		return v.Value(), nil
 	}
 	tflog.Debug(ctx, "creating new kube client")
 
-	kubeconfig, err := GetKubeConfig(ctx, dp, cfg)
+	kubeconfig, err := GetKubeConfigForCluster(ctx, settings, cfg, clusterName, endpointOverride)
 	if err != nil {
 		return nil, err
 	}
@@ -175,6 +401,8 @@ func GetKubeClient(ctx context.Context, cfg aws.Config, dp awsconfig.AWSDataplan
 	if err != nil {
 		return nil, fmt.Errorf("failed to create kube client config: %w", err)
 	}
+	restConfig.QPS = settings.kubeQPS()
+	restConfig.Burst = settings.kubeBurst()
 
 	scheme := runtime.NewScheme()
 	if err = clientgoscheme.AddToScheme(scheme); err != nil {
@@ -198,42 +426,75 @@ func GetKubeClient(ctx context.Context, cfg aws.Config, dp awsconfig.AWSDataplan
 	if err != nil {
 		return nil, fmt.Errorf("failed to create kube client: %w", err)
 	}
-	rClient = &RetryableClient{Client: kubeClient}
 
-	kubeClientCache.Set("kubeClient", rClient, cacheTimeout)
+	// fail early on authorization errors, these are not fixed by retrying. Newly created access entries can take a
+	// few seconds to propagate so the check is retried briefly.
+	if err := retry.Do(ctx, retry.WithMaxRetries(3, retry.NewExponential(time.Second*2)), func(ctx context.Context) error {
+		err := kubeClient.List(ctx, &corev1.NamespaceList{}, client.Limit(1))
+		if k8serrors.IsUnauthorized(err) || k8serrors.IsForbidden(err) {
+			tflog.Debug(ctx, "kube API denied access: "+err.Error())
+			return retry.RetryableError(fmt.Errorf("%w: %w", ErrKubeUnauthorized, err))
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	rClient = &RetryableClient{Client: kubeClient, settings: settings}
+
+	kubeClientCache.Set(key, rClient, cacheTimeout)
 
 	return
 }
 
+// FieldManager is the field manager used for server side applies.
+const FieldManager = "terraform-provider-dataplane"
+
 func ApplyManifests(ctx context.Context, kubeClient *RetryableClient, manifestYamlsCombined string) (d diag.Diagnostics) {
-	manifestYamls := strings.Split(manifestYamlsCombined, "\n---\n")
-	for _, manifestYaml := range manifestYamls {
-		u := &unstructured.Unstructured{}
+	objs, err := parseManifests(manifestYamlsCombined)
+	if err != nil {
+		d.AddError("Failed to unmarshal manifest", err.Error())
+		return
+	}
+	return applyObjects(ctx, kubeClient, "manifests", objs)
+}
 
+func parseManifests(manifestYamlsCombined string) ([]*unstructured.Unstructured, error) {
+	objs := []*unstructured.Unstructured{}
+	for _, manifestYaml := range strings.Split(manifestYamlsCombined, "\n---\n") {
+		u := &unstructured.Unstructured{}
 		if err := yaml.Unmarshal([]byte(manifestYaml), u); err != nil {
-			d.AddError("Failed to unmarshal manifest", err.Error())
-			return
+			return nil, err
 		}
+		objs = append(objs, u)
+	}
+	return objs, nil
+}
+
+func applyObjects(ctx context.Context, kubeClient *RetryableClient, name string, objs []*unstructured.Unstructured) (d diag.Diagnostics) {
+	if kubeClient.DryRun() {
+		return dryRunApplyObjects(ctx, kubeClient, name, objs)
+	}
 
-		tflog.Info(ctx, "Applying object", map[string]any{
+	for _, u := range objs {
+		tflog.Debug(ctx, "Applying object", map[string]any{
 			"kind": u.GetKind(),
 			"name": u.GetName(),
+			"obj":  RedactObject(u),
 		})
 
+		// Objects are applied server side so that fields set by Flux while reconciling are left alone. A conflict
+		// means another manager changed a field the provider sets, in which case the provider takes the field back.
 		if err := retry.Do(ctx, retry.WithMaxRetries(5, retry.NewExponential(time.Second)), func(ctx context.Context) error {
-			ug := u.DeepCopy()
-			if err := kubeClient.Get(ctx, client.ObjectKeyFromObject(ug), ug); err != nil {
-				if k8serrors.IsNotFound(err) {
-					if err := kubeClient.Create(ctx, u); err != nil {
-						return retry.RetryableError(err)
-					}
-					return nil
-				}
-				return retry.RetryableError(err)
+			err := kubeClient.Client.Patch(ctx, u.DeepCopy(), client.Apply, client.FieldOwner(FieldManager))
+			if k8serrors.IsConflict(err) {
+				tflog.Debug(ctx, "field conflict applying object, forcing ownership", map[string]any{
+					"kind":  u.GetKind(),
+					"name":  u.GetName(),
+					"error": err.Error(),
+				})
+				err = kubeClient.Client.Patch(ctx, u.DeepCopy(), client.Apply, client.FieldOwner(FieldManager), client.ForceOwnership)
 			}
-
-			u.SetResourceVersion(ug.GetResourceVersion())
-			if err := kubeClient.Update(ctx, u); err != nil {
+			if err != nil {
 				return retry.RetryableError(err)
 			}
 			return nil
@@ -245,25 +506,115 @@ func ApplyManifests(ctx context.Context, kubeClient *RetryableClient, manifestYa
 	return
 }
 
-func RenderAndApplyTemplate(ctx context.Context, kubeClient *RetryableClient, name string, templateData []byte, data map[string]string) (d diag.Diagnostics) {
+// TemplateOptions configures how RenderAndApplyTemplate applies a template. The zero value applies the rendered
+// objects without pruning.
+type TemplateOptions struct {
+	// Prune labels the objects as owned by the template and deletes owned objects that are no longer part of the
+	// render.
+	Prune bool
+	// InventoryNamespace is the namespace of the config map recording the kinds applied by the template. Required
+	// when Prune is set.
+	InventoryNamespace string
+}
+
+// RenderAndApplyTemplate renders a manifest template and applies the resulting objects, pruning stale objects when
+// requested by the options.
+func RenderAndApplyTemplate(ctx context.Context, kubeClient *RetryableClient, name string, templateData []byte, data map[string]string, opts TemplateOptions) (d diag.Diagnostics) {
 	tflog.Debug(ctx, "rendering manifest template "+name)
-	t, err := template.New(name).Parse(string(templateData))
+	objs, renderHash, err := renderTemplate(name, templateData, data, opts)
 	if err != nil {
-		d.AddError("error parsing manifest template "+name, err.Error())
+		d.AddError("error rendering manifest template "+name, err.Error())
+		return
+	}
+
+	d.Append(applyObjects(ctx, kubeClient, name, objs)...)
+	if d.HasError() || !opts.Prune {
 		return
 	}
 
+	d.Append(pruneOwnedObjects(ctx, kubeClient, opts.InventoryNamespace, name, renderHash, objs)...)
+	return
+}
+
+// renderTemplate renders a manifest template into objects. With pruning the objects are labeled as owned by the
+// template and the hash of the render is returned.
+func renderTemplate(name string, templateData []byte, data map[string]string, opts TemplateOptions) (objs []*unstructured.Unstructured, renderHash string, err error) {
+	if opts.Prune && opts.InventoryNamespace == "" {
+		return nil, "", fmt.Errorf("pruning requires an inventory namespace")
+	}
+
+	t, err := template.New(name).Parse(string(templateData))
+	if err != nil {
+		return nil, "", fmt.Errorf("error parsing template: %w", err)
+	}
+
 	b := bytes.NewBuffer(nil)
 	if err := t.Execute(b, data); err != nil {
-		d.AddError("error render manifest template "+name, err.Error())
-		return
+		return nil, "", fmt.Errorf("error executing template: %w", err)
+	}
+
+	objs, err = parseManifests(b.String())
+	if err != nil {
+		return nil, "", fmt.Errorf("error unmarshalling manifests: %w", err)
+	}
+	if opts.Prune {
+		renderHash = labelOwnedObjects(name, b.Bytes(), objs)
 	}
+	return objs, renderHash, nil
+}
+
+// WaitForCRDsEstablished waits until all CRDs in the given API group, or its subgroups, report the Established
+// condition.
+func WaitForCRDsEstablished(ctx context.Context, kubeClient *RetryableClient, group string, timeout time.Duration) (d diag.Diagnostics) {
+	pending := []string{}
+	err := retry.Do(ctx, retry.WithMaxDuration(timeout, retry.NewConstant(time.Second*2)), func(ctx context.Context) error {
+		crds := apiextensionsv1.CustomResourceDefinitionList{}
+		if err := kubeClient.List(ctx, &crds); err != nil {
+			return retry.RetryableError(err)
+		}
+
+		pending = []string{}
+		found := 0
+		for _, crd := range crds.Items {
+			if crd.Spec.Group != group && !strings.HasSuffix(crd.Spec.Group, "."+group) {
+				continue
+			}
+			found++
+			established := false
+			for _, c := range crd.Status.Conditions {
+				if c.Type == apiextensionsv1.Established && c.Status == apiextensionsv1.ConditionTrue {
+					established = true
+					break
+				}
+			}
+			if !established {
+				pending = append(pending, crd.Name)
+			}
+		}
 
-	return ApplyManifests(ctx, kubeClient, b.String())
+		if found == 0 {
+			return retry.RetryableError(fmt.Errorf("no CRDs found for group %s", group))
+		}
+		if len(pending) > 0 {
+			tflog.Debug(ctx, "waiting for CRDs to be established", map[string]any{"crds": pending})
+			return retry.RetryableError(fmt.Errorf("CRDs not established: %s", strings.Join(pending, ", ")))
+		}
+		return nil
+	})
+	if err != nil {
+		d.AddError(fmt.Sprintf("timeout waiting for %s CRDs to be established after %s", group, timeout), err.Error())
+	}
+	return
 }
 
 type RetryableClient struct {
-	Client client.Client
+	Client   client.Client
+	settings ClientSettings
+}
+
+// DryRun reports whether the client was created for a dry run, manifests are applied with server side dry run.
+func (r *RetryableClient) DryRun() bool {
+	return r.settings.DryRun
 }
 
 var retrylimits = retry.WithMaxRetries(20, retry.NewConstant(time.Second*20))
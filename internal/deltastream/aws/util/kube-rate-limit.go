@@ -0,0 +1,26 @@
+// Copyright (c) DeltaStream, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package util
+
+// The client-go defaults of 5 QPS with a burst of 10 throttle large installs.
+const (
+	DefaultKubeQPS   = 50
+	DefaultKubeBurst = 100
+)
+
+// kubeQPS returns the client side rate limit of requests to the kube API server.
+func (s ClientSettings) kubeQPS() float32 {
+	if s.KubeQPS > 0 {
+		return float32(s.KubeQPS)
+	}
+	return DefaultKubeQPS
+}
+
+// kubeBurst returns the burst allowed above the client side rate limit of requests to the kube API server.
+func (s ClientSettings) kubeBurst() int {
+	if s.KubeBurst > 0 {
+		return s.KubeBurst
+	}
+	return DefaultKubeBurst
+}
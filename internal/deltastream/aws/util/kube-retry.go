@@ -0,0 +1,32 @@
+// Copyright (c) DeltaStream, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package util
+
+import (
+	"time"
+
+	"github.com/sethvargo/go-retry"
+)
+
+const (
+	DefaultKubeMaxRetries = 5
+	DefaultKubeMaxBackoff = time.Minute
+)
+
+// RetryBackoff returns a jittered exponential backoff starting at 5s, capped at the maximum delay of the client
+// settings. Backoffs are stateful, every retry.Do must use a new one.
+func (r *RetryableClient) RetryBackoff() retry.Backoff {
+	maxRetries := DefaultKubeMaxRetries
+	if r.settings.KubeMaxRetries > 0 {
+		maxRetries = r.settings.KubeMaxRetries
+	}
+	maxBackoff := DefaultKubeMaxBackoff
+	if r.settings.KubeMaxBackoff > 0 {
+		maxBackoff = r.settings.KubeMaxBackoff
+	}
+
+	b := retry.WithJitterPercent(20, retry.NewExponential(time.Second*5))
+	b = retry.WithCappedDuration(maxBackoff, b)
+	return retry.WithMaxRetries(uint64(maxRetries), b)
+}
@@ -2,9 +2,11 @@ package util
 
 import (
 	"context"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
 func LogError(ctx context.Context, d diag.Diagnostics, summary string, err error) diag.Diagnostics {
@@ -12,3 +14,69 @@ func LogError(ctx context.Context, d diag.Diagnostics, summary string, err error
 	d.AddError(summary, err.Error())
 	return d
 }
+
+const redacted = "***"
+
+var sensitiveKeyMarkers = []string{"token", "secret", "password", "credential", "apikey", "privatekey"}
+
+// IsSensitiveKey reports whether a key name looks like it holds a credential
+func IsSensitiveKey(key string) bool {
+	k := strings.ToLower(key)
+	if strings.HasSuffix(k, "arn") {
+		return false
+	}
+	for _, marker := range sensitiveKeyMarkers {
+		if strings.Contains(k, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// RedactSettings returns a copy of the settings suitable for logging, values of keys that look like credentials are
+// replaced unless they hold an ARN.
+func RedactSettings(settings map[string][]byte) map[string]string {
+	out := make(map[string]string, len(settings))
+	for k, v := range settings {
+		if IsSensitiveKey(k) && !strings.HasPrefix(string(v), "arn:") {
+			out[k] = redacted
+			continue
+		}
+		out[k] = string(v)
+	}
+	return out
+}
+
+// RedactObject returns a copy of the object content suitable for logging. Secret data and fields that look like
+// credentials are replaced.
+func RedactObject(u *unstructured.Unstructured) map[string]any {
+	obj := u.DeepCopy().Object
+	if u.GetKind() == "Secret" {
+		for _, field := range []string{"data", "stringData"} {
+			if data, ok := obj[field].(map[string]any); ok {
+				for k := range data {
+					data[k] = redacted
+				}
+			}
+		}
+	}
+	redactFields(obj)
+	return obj
+}
+
+func redactFields(v any) {
+	switch val := v.(type) {
+	case map[string]any:
+		for k, child := range val {
+			if _, isString := child.(string); isString && IsSensitiveKey(k) {
+				val[k] = redacted
+				continue
+			}
+			redactFields(child)
+		}
+	case []any:
+		for _, child := range val {
+			redactFields(child)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+// Copyright (c) DeltaStream, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package util
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestIsSensitiveKey(t *testing.T) {
+	tests := []struct {
+		key  string
+		want bool
+	}{
+		{key: "apiToken", want: true},
+		{key: "KAFKA_SASL_PASSWORD", want: true},
+		{key: "client-secret", want: true},
+		{key: "awsCredentials", want: true},
+		{key: "signing.privateKey", want: true},
+		{key: "datadogApiKey", want: true},
+		{key: "kafka.sasl.password", want: true},
+		{key: "rdsSecretArn", want: false},
+		{key: "credentialsRoleARN", want: false},
+		{key: "clusterName", want: false},
+		{key: "kafka.bootstrap.servers", want: false},
+		{key: "", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			if got := IsSensitiveKey(tt.key); got != tt.want {
+				t.Errorf("IsSensitiveKey(%q) = %v, want %v", tt.key, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedactSettings(t *testing.T) {
+	settings := map[string][]byte{
+		"clusterName":             []byte("dp-1"),
+		"kafka.bootstrap.servers": []byte("b-1.kafka:9096"),
+		"kafka.sasl.password":     []byte("hunter2"),
+		"apiToken":                []byte("abc123"),
+		"rdsSecretArn":            []byte("arn:aws:secretsmanager:us-west-2:123456789012:secret:rds"),
+		"kafkaSecret":             []byte("arn:aws:secretsmanager:us-west-2:123456789012:secret:kafka"),
+		"emptySecret":             []byte(""),
+	}
+	want := map[string]string{
+		"clusterName":             "dp-1",
+		"kafka.bootstrap.servers": "b-1.kafka:9096",
+		"kafka.sasl.password":     redacted,
+		"apiToken":                redacted,
+		"rdsSecretArn":            "arn:aws:secretsmanager:us-west-2:123456789012:secret:rds",
+		"kafkaSecret":             "arn:aws:secretsmanager:us-west-2:123456789012:secret:kafka",
+		"emptySecret":             redacted,
+	}
+
+	if got := RedactSettings(settings); !reflect.DeepEqual(got, want) {
+		t.Errorf("RedactSettings() = %v, want %v", got, want)
+	}
+	if string(settings["apiToken"]) != "abc123" {
+		t.Errorf("RedactSettings() modified its input")
+	}
+}
+
+func TestRedactObject(t *testing.T) {
+	secret := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"metadata":   map[string]any{"name": "cluster-settings", "namespace": "cluster-config"},
+		"data":       map[string]any{"clusterName": "ZHAtMQ==", "apiToken": "YWJjMTIz"},
+		"stringData": map[string]any{"region": "us-west-2"},
+	}}
+	deployment := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   map[string]any{"name": "dp-manager"},
+		"spec": map[string]any{
+			"replicas": int64(2),
+			"template": map[string]any{"spec": map[string]any{"containers": []any{
+				map[string]any{"name": "manager", "image": "deltastream/dp-manager:1.2.3", "env": []any{
+					map[string]any{"name": "LOG_LEVEL", "value": "info"},
+				}, "args": map[string]any{"password": "hunter2", "roleArn": "arn:aws:iam::123456789012:role/dp"}},
+			}}},
+		},
+	}}
+
+	tests := []struct {
+		name string
+		obj  *unstructured.Unstructured
+		want map[string]any
+	}{
+		{
+			name: "secret data is masked",
+			obj:  secret,
+			want: map[string]any{
+				"apiVersion": "v1",
+				"kind":       "Secret",
+				"metadata":   map[string]any{"name": "cluster-settings", "namespace": "cluster-config"},
+				"data":       map[string]any{"clusterName": redacted, "apiToken": redacted},
+				"stringData": map[string]any{"region": redacted},
+			},
+		},
+		{
+			name: "nested credential fields are masked",
+			obj:  deployment,
+			want: map[string]any{
+				"apiVersion": "apps/v1",
+				"kind":       "Deployment",
+				"metadata":   map[string]any{"name": "dp-manager"},
+				"spec": map[string]any{
+					"replicas": int64(2),
+					"template": map[string]any{"spec": map[string]any{"containers": []any{
+						map[string]any{"name": "manager", "image": "deltastream/dp-manager:1.2.3", "env": []any{
+							map[string]any{"name": "LOG_LEVEL", "value": "info"},
+						}, "args": map[string]any{"password": redacted, "roleArn": "arn:aws:iam::123456789012:role/dp"}},
+					}}},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := tt.obj.DeepCopy()
+			if got := RedactObject(tt.obj); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("RedactObject() = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(tt.obj.Object, original.Object) {
+				t.Errorf("RedactObject() modified its input")
+			}
+		})
+	}
+}
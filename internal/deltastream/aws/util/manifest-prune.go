@@ -0,0 +1,151 @@
+// Copyright (c) DeltaStream, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package util
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	ManagedByLabel  = "app.kubernetes.io/managed-by"
+	ManagedByValue  = FieldManager
+	TemplateLabel   = "dataplane.deltastream.io/template"
+	RenderHashLabel = "dataplane.deltastream.io/render-hash"
+)
+
+// kinds that are never pruned, deleting them would cascade to objects that are not owned by the template.
+var unprunableKinds = map[schema.GroupKind]bool{
+	{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"}: true,
+	{Group: "", Kind: "Namespace"}:                                    true,
+}
+
+var templateLabelRegex = regexp.MustCompile(`[^a-z0-9]+`)
+
+func templateLabelValue(name string) string {
+	return strings.Trim(templateLabelRegex.ReplaceAllString(strings.ToLower(name), "-"), "-")
+}
+
+// labelOwnedObjects marks the objects as owned by the named template and returns the hash of the render.
+func labelOwnedObjects(name string, rendered []byte, objs []*unstructured.Unstructured) string {
+	sum := sha256.Sum256(rendered)
+	renderHash := hex.EncodeToString(sum[:])[:16]
+	for _, u := range objs {
+		l := u.GetLabels()
+		if l == nil {
+			l = map[string]string{}
+		}
+		l[ManagedByLabel] = ManagedByValue
+		l[TemplateLabel] = templateLabelValue(name)
+		l[RenderHashLabel] = renderHash
+		u.SetLabels(l)
+	}
+	return renderHash
+}
+
+// pruneOwnedObjects deletes objects owned by the named template that were not part of the render with the given hash.
+// The kinds applied by each render are recorded in an inventory config map so that objects of kinds dropped from the
+// template altogether are found as well, the inventory is kept in the given namespace.
+func pruneOwnedObjects(ctx context.Context, kubeClient *RetryableClient, inventoryNamespace string, name string, renderHash string, objs []*unstructured.Unstructured) (d diag.Diagnostics) {
+	inventory := &corev1.ConfigMap{}
+	inventory.Name = "dataplane-manifests-" + templateLabelValue(name)
+	inventory.Namespace = inventoryNamespace
+	if err := kubeClient.Get(ctx, client.ObjectKeyFromObject(inventory), inventory); err != nil && !k8serrors.IsNotFound(err) {
+		d.AddError("error reading manifest inventory "+inventory.Name, err.Error())
+		return
+	}
+
+	current := map[string]bool{}
+	for _, u := range objs {
+		current[u.GroupVersionKind().GroupVersion().String()+"/"+u.GetKind()] = true
+	}
+	kinds := map[string]bool{}
+	for k := range current {
+		kinds[k] = true
+	}
+	for _, k := range strings.Split(inventory.Data["kinds"], "\n") {
+		if k != "" {
+			kinds[k] = true
+		}
+	}
+
+	selector, err := labels.Parse(fmt.Sprintf("%s=%s,%s=%s,%s!=%s", ManagedByLabel, ManagedByValue, TemplateLabel, templateLabelValue(name), RenderHashLabel, renderHash))
+	if err != nil {
+		d.AddError("error building prune selector", err.Error())
+		return
+	}
+
+	for k := range kinds {
+		idx := strings.LastIndex(k, "/")
+		gv, err := schema.ParseGroupVersion(k[:idx])
+		if err != nil {
+			continue
+		}
+		gvk := gv.WithKind(k[idx+1:])
+		if unprunableKinds[gvk.GroupKind()] {
+			continue
+		}
+
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+		if err := kubeClient.Client.List(ctx, list, client.MatchingLabelsSelector{Selector: selector}); err != nil {
+			if meta.IsNoMatchError(err) || k8serrors.IsNotFound(err) {
+				continue
+			}
+			d.AddError("error listing "+gvk.Kind+" objects to prune", err.Error())
+			return
+		}
+
+		for i := range list.Items {
+			stale := &list.Items[i]
+			if kubeClient.DryRun() {
+				d.AddWarning("dry run: "+objectRef(stale)+" would be pruned", "the object is no longer part of the "+name+" template")
+				continue
+			}
+			tflog.Info(ctx, "pruning object removed from template "+name, map[string]any{
+				"kind":      stale.GetKind(),
+				"namespace": stale.GetNamespace(),
+				"name":      stale.GetName(),
+			})
+			if err := kubeClient.Delete(ctx, stale); err != nil && !k8serrors.IsNotFound(err) {
+				d.AddError("error pruning "+stale.GetKind()+" "+stale.GetName(), err.Error())
+				return
+			}
+		}
+	}
+
+	if kubeClient.DryRun() {
+		return
+	}
+
+	currentKinds := []string{}
+	for k := range current {
+		currentKinds = append(currentKinds, k)
+	}
+	sort.Strings(currentKinds)
+	if _, err := controllerutil.CreateOrUpdate(ctx, kubeClient.Client, inventory, func() error {
+		inventory.Labels = map[string]string{ManagedByLabel: ManagedByValue, TemplateLabel: templateLabelValue(name)}
+		inventory.Data = map[string]string{"kinds": strings.Join(currentKinds, "\n")}
+		return nil
+	}); err != nil {
+		d.AddError("error updating manifest inventory "+inventory.Name, err.Error())
+	}
+	return
+}
@@ -0,0 +1,57 @@
+// Copyright (c) DeltaStream, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package util
+
+import (
+	"testing"
+)
+
+const pruneTestTemplate = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{ .Name }}
+  namespace: default
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: {{ .Name }}
+  namespace: default`
+
+func TestRenderTemplatePruneOption(t *testing.T) {
+	tests := []struct {
+		name       string
+		opts       TemplateOptions
+		wantLabels bool
+		wantErr    bool
+	}{
+		{name: "default does not prune", opts: TemplateOptions{}},
+		{name: "inventory namespace alone does not prune", opts: TemplateOptions{InventoryNamespace: "kube-system"}},
+		{name: "prune labels owned objects", opts: TemplateOptions{Prune: true, InventoryNamespace: "kube-system"}, wantLabels: true},
+		{name: "prune requires inventory namespace", opts: TemplateOptions{Prune: true}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			objs, renderHash, err := renderTemplate("data plane", []byte(pruneTestTemplate), map[string]string{"Name": "dataplane"}, tt.opts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("renderTemplate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(objs) != 2 {
+				t.Fatalf("renderTemplate() returned %d objects, want 2", len(objs))
+			}
+			if got := renderHash != ""; got != tt.wantLabels {
+				t.Errorf("renderTemplate() render hash = %q, want hash %v", renderHash, tt.wantLabels)
+			}
+			for _, u := range objs {
+				labels := u.GetLabels()
+				if got := labels[TemplateLabel] == "data-plane" && labels[ManagedByLabel] == ManagedByValue && labels[RenderHashLabel] == renderHash; got != tt.wantLabels {
+					t.Errorf("%s %s labels = %v, want owned %v", u.GetKind(), u.GetName(), labels, tt.wantLabels)
+				}
+			}
+		})
+	}
+}
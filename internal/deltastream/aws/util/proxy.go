@@ -0,0 +1,25 @@
+// Copyright (c) DeltaStream, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package util
+
+import (
+	"net/http"
+	"net/url"
+)
+
+// hasProxy reports whether a proxy is set in the provider configuration.
+func (s ClientSettings) hasProxy() bool {
+	return s.Proxy.HTTPProxy != "" || s.Proxy.HTTPSProxy != ""
+}
+
+func (s ClientSettings) proxyFunc() func(*http.Request) (*url.URL, error) {
+	if !s.hasProxy() {
+		return http.ProxyFromEnvironment
+	}
+
+	fn := s.Proxy.ProxyFunc()
+	return func(req *http.Request) (*url.URL, error) {
+		return fn(req.URL)
+	}
+}
@@ -0,0 +1,27 @@
+// Copyright (c) DeltaStream, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package util
+
+import "sort"
+
+// MergeTags returns the union of the tag sets. When a key is present in several sets the value of the last set wins.
+func MergeTags(tagSets ...map[string]string) map[string]string {
+	merged := map[string]string{}
+	for _, tags := range tagSets {
+		for k, v := range tags {
+			merged[k] = v
+		}
+	}
+	return merged
+}
+
+// SortedTagKeys returns the keys of the tags in lexical order, so that tags are sent to AWS in a stable order.
+func SortedTagKeys(tags map[string]string) []string {
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
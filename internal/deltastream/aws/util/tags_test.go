@@ -0,0 +1,27 @@
+// Copyright (c) DeltaStream, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package util
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMergeTagsResourceWins(t *testing.T) {
+	defaults := map[string]string{"team": "platform", "cost-center": "1234"}
+
+	got := MergeTags(defaults, map[string]string{"team": "streaming", "env": "dev"})
+	want := map[string]string{"team": "streaming", "cost-center": "1234", "env": "dev"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("MergeTags() = %v, want %v", got, want)
+	}
+}
+
+func TestMergeTagsDoesNotModifyInputs(t *testing.T) {
+	defaults := map[string]string{"team": "platform"}
+	MergeTags(defaults, map[string]string{"team": "streaming"})
+	if defaults["team"] != "platform" {
+		t.Errorf("MergeTags() modified its input: %v", defaults)
+	}
+}
@@ -0,0 +1,195 @@
+// Copyright (c) DeltaStream, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package aws
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+
+	awsconfig "github.com/deltastreaminc/terraform-provider-dataplane/internal/deltastream/aws/config"
+)
+
+var configurationPath = path.Root("configuration")
+
+// validateConfiguration checks constraints between configuration attributes that cannot be expressed with attribute
+// validators. Unknown values are skipped, they are validated once known.
+func validateConfiguration(ctx context.Context, dp awsconfig.AWSDataplane) (d diag.Diagnostics) {
+	if dp.ClusterConfiguration.IsNull() || dp.ClusterConfiguration.IsUnknown() {
+		return
+	}
+
+	clusterConfig, diags := dp.ClusterConfigurationData(ctx)
+	d.Append(diags...)
+	if d.HasError() {
+		return
+	}
+
+	d.Append(validateAcmeEmail(clusterConfig.O11yTlsMode, clusterConfig.O11yAcmeEmail, "o11y_tls_mode", "o11y_acme_email")...)
+	d.Append(validateAcmeEmail(clusterConfig.ApiTlsMode, clusterConfig.ApiAcmeEmail, "api_tls_mode", "api_acme_email")...)
+	d.Append(validateTlsCertificateArn(clusterConfig.O11yTlsMode, clusterConfig.O11yTlsCertificateArn, "o11y_tls_mode", "o11y_tls_certificate_arn")...)
+	d.Append(validateTlsCertificateArn(clusterConfig.ApiTlsMode, clusterConfig.ApiTlsCertificateArn, "api_tls_mode", "api_tls_certificate_arn")...)
+	d.Append(validateRdsAuth(clusterConfig)...)
+	d.Append(validateKafkaAuth(clusterConfig)...)
+	d.Append(validateCustomCredentials(clusterConfig)...)
+	d.Append(validateIpFamily(clusterConfig)...)
+	d.Append(validateImageReplicaRegions(clusterConfig)...)
+	d.Append(validateCw2Loki(clusterConfig)...)
+	d.Append(validateImageSignaturePolicy(clusterConfig)...)
+	return
+}
+
+// validateImageSignaturePolicy requires the public key to verify signatures against, signatures are only verified
+// when images are copied without a rate limit.
+func validateImageSignaturePolicy(clusterConfig awsconfig.ClusterConfiguration) (d diag.Diagnostics) {
+	if clusterConfig.ImageSignaturePolicy.IsUnknown() || clusterConfig.ImageSignaturePolicy.ValueString() != awsconfig.ImageSignaturePolicyVerify {
+		return
+	}
+
+	if clusterConfig.ImageSignaturePublicKey.IsNull() {
+		d.AddAttributeError(configurationPath.AtName("image_signature_public_key"), "Missing image signature public key", "image_signature_public_key is required when image_signature_policy is "+awsconfig.ImageSignaturePolicyVerify+".")
+	}
+	if !clusterConfig.ImageDeliveryMode.IsUnknown() && clusterConfig.ImageDeliveryMode.ValueString() != awsconfig.ImageDeliveryModeCopy {
+		d.AddAttributeError(configurationPath.AtName("image_signature_policy"), "Image signature verification not supported", "image_signature_policy "+awsconfig.ImageSignaturePolicyVerify+" requires image_delivery_mode to be "+awsconfig.ImageDeliveryModeCopy+".")
+	}
+	// the rate limited source does not expose the signatures of the source image, an invalid limit is reported by
+	// the attribute validator
+	if limiter, _ := imageCopyRateLimiter(clusterConfig); limiter != nil {
+		d.AddAttributeError(configurationPath.AtName("image_copy_rate_limit"), "Image copy rate limit not supported", "image_copy_rate_limit cannot be set when image_signature_policy is "+awsconfig.ImageSignaturePolicyVerify+", signatures are not copied through the rate limiter.")
+	}
+	return
+}
+
+// validateCw2Loki requires the cw2loki SQS URL and role to be configured together, CloudWatch ingestion is only
+// rendered when both are set.
+func validateCw2Loki(clusterConfig awsconfig.ClusterConfiguration) (d diag.Diagnostics) {
+	if clusterConfig.Cw2LokiSqsUrl.IsUnknown() || clusterConfig.Cw2LokiRoleArn.IsUnknown() {
+		return
+	}
+
+	sqsUrlSet, roleSet := !clusterConfig.Cw2LokiSqsUrl.IsNull(), !clusterConfig.Cw2LokiRoleArn.IsNull()
+	switch {
+	case sqsUrlSet && !roleSet:
+		d.AddAttributeError(configurationPath.AtName("cw2loki_role_arn"), "Missing cw2loki role", "cw2loki_role_arn is required when cw2loki_sqs_url is set.")
+	case roleSet && !sqsUrlSet:
+		d.AddAttributeError(configurationPath.AtName("cw2loki_sqs_url"), "Missing cw2loki SQS URL", "cw2loki_sqs_url is required when cw2loki_role_arn is set.")
+	}
+	return
+}
+
+// validateImageReplicaRegions rejects replica regions when images are not copied.
+func validateImageReplicaRegions(clusterConfig awsconfig.ClusterConfiguration) (d diag.Diagnostics) {
+	if clusterConfig.ImageReplicaRegions.IsNull() || clusterConfig.ImageReplicaRegions.IsUnknown() || clusterConfig.ImageDeliveryMode.IsUnknown() {
+		return
+	}
+	if clusterConfig.ImageDeliveryMode.ValueString() != awsconfig.ImageDeliveryModeCopy && len(clusterConfig.ImageReplicaRegions.Elements()) > 0 {
+		d.AddAttributeError(configurationPath.AtName("image_replica_regions"), "Image replica regions not supported", "image_replica_regions requires image_delivery_mode to be "+awsconfig.ImageDeliveryModeCopy+".")
+	}
+	return
+}
+
+// validateIpFamily requires the VPC IPv6 CIDR for dual-stack clusters.
+func validateIpFamily(clusterConfig awsconfig.ClusterConfiguration) (d diag.Diagnostics) {
+	if clusterConfig.IpFamily.ValueString() == awsconfig.IpFamilyDualStack && clusterConfig.VpcIpv6Cidr.IsNull() {
+		d.AddAttributeError(configurationPath.AtName("vpc_ipv6_cidr"), "Missing VPC IPv6 CIDR", "vpc_ipv6_cidr is required when ip_family is dualstack.")
+	}
+	return
+}
+
+// validateCustomCredentials requires the custom credentials image and role to be configured together, the plugin is
+// enabled by the role while the container is deployed from the image.
+func validateCustomCredentials(clusterConfig awsconfig.ClusterConfiguration) (d diag.Diagnostics) {
+	if clusterConfig.CustomCredentialsImage.IsUnknown() || clusterConfig.CustomCredentialsRoleARN.IsUnknown() {
+		return
+	}
+
+	imageSet, roleSet := !clusterConfig.CustomCredentialsImage.IsNull(), !clusterConfig.CustomCredentialsRoleARN.IsNull()
+	switch {
+	case imageSet && !roleSet:
+		d.AddAttributeError(configurationPath.AtName("custom_credentials_role_arn"), "Missing custom credentials role", "custom_credentials_role_arn is required when custom_credentials_image is set.")
+	case roleSet && !imageSet:
+		d.AddAttributeError(configurationPath.AtName("custom_credentials_image"), "Missing custom credentials image", "custom_credentials_image is required when custom_credentials_role_arn is set.")
+	}
+
+	if imageSet {
+		if _, _, _, err := parseImageReference(clusterConfig.CustomCredentialsImage.ValueString()); err != nil {
+			d.AddAttributeError(configurationPath.AtName("custom_credentials_image"), "Invalid custom credentials image", err.Error())
+		}
+	}
+	return
+}
+
+// validateKafkaAuth requires the settings of the selected kafka authentication mode, the SCRAM secret contents are
+// checked when the deployment config is rendered.
+func validateKafkaAuth(clusterConfig awsconfig.ClusterConfiguration) (d diag.Diagnostics) {
+	if clusterConfig.KafkaAuthMode.IsUnknown() {
+		return
+	}
+
+	if clusterConfig.KafkaAuthMode.ValueString() == awsconfig.KafkaAuthModeScram {
+		if !clusterConfig.KafkaScramSecret.IsUnknown() && (clusterConfig.KafkaScramSecret.IsNull() || clusterConfig.KafkaScramSecret.ValueString() == "") {
+			d.AddAttributeError(configurationPath.AtName("kafka_scram_secret"), "Missing kafka SCRAM secret", "kafka_scram_secret is required when kafka_auth_mode is scram.")
+		}
+		return
+	}
+
+	for attr, v := range map[string]basetypes.StringValue{
+		"kafka_role_arn":         clusterConfig.KafkaRoleArn,
+		"kafka_role_external_id": clusterConfig.KafkaRoleExternalId,
+	} {
+		if v.IsNull() {
+			d.AddAttributeError(configurationPath.AtName(attr), "Missing kafka IAM authentication setting", attr+" is required when kafka_auth_mode is iam.")
+		}
+	}
+	return
+}
+
+func validateRdsAuth(clusterConfig awsconfig.ClusterConfiguration) (d diag.Diagnostics) {
+	if clusterConfig.RdsAuthMode.IsUnknown() || clusterConfig.RdsAuthMode.ValueString() != awsconfig.RdsAuthModeIam {
+		return
+	}
+
+	for attr, v := range map[string]basetypes.StringValue{
+		"rds_iam_role_arn": clusterConfig.RdsIamRoleArn,
+		"rds_host":         clusterConfig.RdsHost,
+		"rds_database":     clusterConfig.RdsDatabase,
+		"rds_username":     clusterConfig.RdsUsername,
+	} {
+		if v.IsNull() {
+			d.AddAttributeError(configurationPath.AtName(attr), "Missing RDS IAM authentication setting", attr+" is required when rds_auth_mode is iam.")
+		}
+	}
+	return
+}
+
+func validateAcmeEmail(tlsMode, email basetypes.StringValue, tlsModeAttr, emailAttr string) (d diag.Diagnostics) {
+	if tlsMode.IsUnknown() || email.IsUnknown() {
+		return
+	}
+
+	if tlsMode.ValueString() == "acme" && email.IsNull() {
+		d.AddAttributeError(configurationPath.AtName(emailAttr), "Missing ACME email", emailAttr+" is required when "+tlsModeAttr+" is acme.")
+	}
+	return
+}
+
+func validateTlsCertificateArn(tlsMode, certificateArn basetypes.StringValue, tlsModeAttr, certificateArnAttr string) (d diag.Diagnostics) {
+	if tlsMode.IsUnknown() || certificateArn.IsUnknown() {
+		return
+	}
+
+	switch tlsMode.ValueString() {
+	case "awscert":
+		if certificateArn.IsNull() {
+			d.AddAttributeError(configurationPath.AtName(certificateArnAttr), "Missing TLS certificate ARN", certificateArnAttr+" is required when "+tlsModeAttr+" is awscert.")
+		}
+	default:
+		if !certificateArn.IsNull() {
+			d.AddAttributeWarning(configurationPath.AtName(certificateArnAttr), "Unused TLS certificate ARN", certificateArnAttr+" is ignored when "+tlsModeAttr+" is "+tlsMode.ValueString()+".")
+		}
+	}
+	return
+}
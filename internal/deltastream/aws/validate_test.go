@@ -0,0 +1,52 @@
+// Copyright (c) DeltaStream, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package aws
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+
+	awsconfig "github.com/deltastreaminc/terraform-provider-dataplane/internal/deltastream/aws/config"
+)
+
+func TestValidateImageSignaturePolicy(t *testing.T) {
+	tests := []struct {
+		name          string
+		policy        string
+		rateLimit     basetypes.StringValue
+		wantErrorPath string
+	}{
+		{name: "verify without rate limit", policy: awsconfig.ImageSignaturePolicyVerify, rateLimit: basetypes.NewStringNull()},
+		{name: "verify with rate limit", policy: awsconfig.ImageSignaturePolicyVerify, rateLimit: basetypes.NewStringValue("20Mi"), wantErrorPath: "configuration.image_copy_rate_limit"},
+		{name: "verify with unlimited rate", policy: awsconfig.ImageSignaturePolicyVerify, rateLimit: basetypes.NewStringValue("0")},
+		{name: "verify with unknown rate limit", policy: awsconfig.ImageSignaturePolicyVerify, rateLimit: basetypes.NewStringUnknown()},
+		{name: "accept anything with rate limit", policy: awsconfig.ImageSignaturePolicyAcceptAnything, rateLimit: basetypes.NewStringValue("20Mi")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clusterConfig := awsconfig.ClusterConfiguration{
+				ImageDeliveryMode:       basetypes.NewStringValue(awsconfig.ImageDeliveryModeCopy),
+				ImageSignaturePolicy:    basetypes.NewStringValue(tt.policy),
+				ImageSignaturePublicKey: basetypes.NewStringValue("-----BEGIN PUBLIC KEY-----"),
+				ImageCopyRateLimit:      tt.rateLimit,
+			}
+			d := validateImageSignaturePolicy(clusterConfig)
+			if tt.wantErrorPath == "" {
+				if d.HasError() {
+					t.Fatalf("validateImageSignaturePolicy() = %v, want no error", d)
+				}
+				return
+			}
+			if d.ErrorsCount() != 1 {
+				t.Fatalf("validateImageSignaturePolicy() = %v, want one error", d)
+			}
+			withPath, ok := d.Errors()[0].(diag.DiagnosticWithPath)
+			if !ok || withPath.Path().String() != tt.wantErrorPath {
+				t.Errorf("validateImageSignaturePolicy() error = %v, want error on %s", d.Errors()[0], tt.wantErrorPath)
+			}
+		})
+	}
+}
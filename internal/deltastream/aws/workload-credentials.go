@@ -0,0 +1,98 @@
+// Copyright (c) DeltaStream, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package aws
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	appsv1 "k8s.io/api/apps/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	awsconfig "github.com/deltastreaminc/terraform-provider-dataplane/internal/deltastream/aws/config"
+	"github.com/deltastreaminc/terraform-provider-dataplane/internal/deltastream/aws/util"
+)
+
+// restartedAtAnnotation is set on pod templates to roll the pods of a workload.
+const restartedAtAnnotation = "io.deltastream.tf-deltastream/restartedAt"
+
+// workloadCredentialsModeChanged reports whether the update switches workloads between secret and IAM role
+// credentials.
+func workloadCredentialsModeChanged(oldConfig, newConfig awsconfig.ClusterConfiguration) bool {
+	return oldConfig.WorkloadCredentialsMode.ValueString() != newConfig.WorkloadCredentialsMode.ValueString()
+}
+
+// rolloutWorkloadCredentials restarts the dp-operator and the DeltaStream services after workload_credentials_mode
+// changed. The services read their credentials configuration when they start, without a restart they keep
+// authenticating with the credentials of the previous mode.
+func rolloutWorkloadCredentials(ctx context.Context, cfg aws.Config, dp awsconfig.AWSDataplane, getKubeClient kubeClientFactory) (d diag.Diagnostics) {
+	clusterConfig, diags := dp.ClusterConfigurationData(ctx)
+	d.Append(diags...)
+	if d.HasError() {
+		return
+	}
+
+	kubeClient, diags := getKubeClient(ctx, cfg, dp)
+	d.Append(diags...)
+	if d.HasError() {
+		return
+	}
+
+	tflog.Info(ctx, "workload credentials mode changed, restarting workloads", map[string]any{"mode": clusterConfig.WorkloadCredentialsMode.ValueString()})
+	namespaces := clusterConfig.Namespaces()
+	for _, namespace := range []string{namespaces.DpOperator, namespaces.DeltaStream} {
+		d.Append(restartWorkloads(ctx, kubeClient, namespace)...)
+		if d.HasError() {
+			return
+		}
+	}
+	return
+}
+
+// restartWorkloads rolls the pods of every deployment and statefulset in the namespace.
+func restartWorkloads(ctx context.Context, kubeClient *util.RetryableClient, namespace string) (d diag.Diagnostics) {
+	restartedAt := time.Now().Format(time.RFC3339)
+
+	deployments := appsv1.DeploymentList{}
+	if err := kubeClient.List(ctx, &deployments, client.InNamespace(namespace)); err != nil {
+		d.AddError("error listing "+namespace+" deployments", err.Error())
+		return
+	}
+	for i := range deployments.Items {
+		deployment := &deployments.Items[i]
+		patch := client.MergeFrom(deployment.DeepCopy())
+		if deployment.Spec.Template.Annotations == nil {
+			deployment.Spec.Template.Annotations = map[string]string{}
+		}
+		deployment.Spec.Template.Annotations[restartedAtAnnotation] = restartedAt
+		if err := kubeClient.Patch(ctx, deployment, patch); err != nil {
+			d.AddError("error restarting deployment "+namespace+"/"+deployment.Name, err.Error())
+			return
+		}
+	}
+
+	statefulSets := appsv1.StatefulSetList{}
+	if err := kubeClient.List(ctx, &statefulSets, client.InNamespace(namespace)); err != nil {
+		d.AddError("error listing "+namespace+" statefulsets", err.Error())
+		return
+	}
+	for i := range statefulSets.Items {
+		statefulSet := &statefulSets.Items[i]
+		patch := client.MergeFrom(statefulSet.DeepCopy())
+		if statefulSet.Spec.Template.Annotations == nil {
+			statefulSet.Spec.Template.Annotations = map[string]string{}
+		}
+		statefulSet.Spec.Template.Annotations[restartedAtAnnotation] = restartedAt
+		if err := kubeClient.Patch(ctx, statefulSet, patch); err != nil {
+			d.AddError("error restarting statefulset "+namespace+"/"+statefulSet.Name, err.Error())
+			return
+		}
+	}
+
+	tflog.Debug(ctx, "restarted workloads", map[string]any{"namespace": namespace, "deployments": len(deployments.Items), "statefulsets": len(statefulSets.Items)})
+	return
+}
@@ -0,0 +1,208 @@
+// Copyright (c) DeltaStream, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package aws
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/sethvargo/go-retry"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	awsconfig "github.com/deltastreaminc/terraform-provider-dataplane/internal/deltastream/aws/config"
+	"github.com/deltastreaminc/terraform-provider-dataplane/internal/deltastream/aws/util"
+)
+
+// maxPodEvents limits the warning events reported for each pod of a workload that is not ready.
+const maxPodEvents = 3
+
+// notReadyWorkload is a deployment or statefulset of the DeltaStream namespace that is not ready.
+type notReadyWorkload struct {
+	name     string
+	reason   string
+	selector *v1.LabelSelector
+}
+
+// waitWorkloadsReady waits until the deployments and statefulsets in the DeltaStream namespace are ready, so that an
+// apply does not succeed while services crash. On timeout every workload that is not ready is reported with the state
+// of its containers and the latest warning events of its pods.
+func waitWorkloadsReady(ctx context.Context, cfg aws.Config, dp awsconfig.AWSDataplane, getKubeClient kubeClientFactory) (d diag.Diagnostics) {
+	clusterConfig, diags := dp.ClusterConfigurationData(ctx)
+	d.Append(diags...)
+	if d.HasError() {
+		return
+	}
+
+	timeout, err := time.ParseDuration(clusterConfig.WorkloadReadyTimeout.ValueString())
+	if err != nil {
+		d.AddError("invalid workload ready timeout", err.Error())
+		return
+	}
+	if timeout == 0 {
+		tflog.Debug(ctx, "workload readiness check disabled")
+		return
+	}
+
+	namespace := clusterConfig.Namespaces().DeltaStream
+	notReady := []notReadyWorkload{}
+	tflog.Debug(ctx, "waiting for workloads to become ready", map[string]any{"namespace": namespace, "timeout": timeout.String()})
+	err = retry.Do(ctx, retry.WithMaxDuration(timeout, retry.NewConstant(10*time.Second)), func(ctx context.Context) error {
+		kubeClient, diags := getKubeClient(ctx, cfg, dp)
+		if diags.HasError() {
+			return retry.RetryableError(util.DiagnosticsError(diags))
+		}
+
+		deployments := appsv1.DeploymentList{}
+		if err := kubeClient.List(ctx, &deployments, client.InNamespace(namespace)); err != nil {
+			return retry.RetryableError(err)
+		}
+		statefulSets := appsv1.StatefulSetList{}
+		if err := kubeClient.List(ctx, &statefulSets, client.InNamespace(namespace)); err != nil {
+			return retry.RetryableError(err)
+		}
+
+		notReady = []notReadyWorkload{}
+		for _, deployment := range deployments.Items {
+			if reason := deploymentNotReadyReason(deployment); reason != "" {
+				notReady = append(notReady, notReadyWorkload{name: "deployment/" + deployment.Name, reason: reason, selector: deployment.Spec.Selector})
+			}
+		}
+		for _, statefulSet := range statefulSets.Items {
+			if reason := statefulSetNotReadyReason(statefulSet); reason != "" {
+				notReady = append(notReady, notReadyWorkload{name: "statefulset/" + statefulSet.Name, reason: reason, selector: statefulSet.Spec.Selector})
+			}
+		}
+		if len(notReady) > 0 {
+			names := make([]string, 0, len(notReady))
+			for _, w := range notReady {
+				names = append(names, w.name)
+			}
+			tflog.Debug(ctx, "workloads not ready", map[string]any{"workloads": names})
+			return retry.RetryableError(fmt.Errorf("%d workloads not ready", len(notReady)))
+		}
+		return nil
+	})
+	if err == nil {
+		tflog.Debug(ctx, "workloads ready", map[string]any{"namespace": namespace})
+		return
+	}
+
+	if len(notReady) == 0 {
+		d.AddError("timeout waiting for workloads to become ready", err.Error())
+		return
+	}
+	kubeClient, diags := getKubeClient(ctx, cfg, dp)
+	for _, w := range notReady {
+		detail := w.reason
+		if !diags.HasError() {
+			if states := podStates(ctx, kubeClient, namespace, w.selector); len(states) > 0 {
+				detail += "\n\n" + strings.Join(states, "\n")
+			}
+		}
+		d.AddError(fmt.Sprintf("%s in namespace %s not ready after %s", w.name, namespace, timeout), detail)
+	}
+	return
+}
+
+// deploymentNotReadyReason returns why the deployment is not ready, empty when it is.
+func deploymentNotReadyReason(deployment appsv1.Deployment) string {
+	replicas := ptr.Deref(deployment.Spec.Replicas, 1)
+	status := deployment.Status
+	switch {
+	case status.ObservedGeneration < deployment.Generation:
+		return fmt.Sprintf("generation %d not observed yet", deployment.Generation)
+	case status.UpdatedReplicas < replicas:
+		return fmt.Sprintf("%d of %d replicas updated", status.UpdatedReplicas, replicas)
+	case status.AvailableReplicas < replicas:
+		return fmt.Sprintf("%d of %d replicas available", status.AvailableReplicas, replicas)
+	}
+	return ""
+}
+
+// statefulSetNotReadyReason returns why the statefulset is not ready, empty when it is.
+func statefulSetNotReadyReason(statefulSet appsv1.StatefulSet) string {
+	replicas := ptr.Deref(statefulSet.Spec.Replicas, 1)
+	status := statefulSet.Status
+	switch {
+	case status.ObservedGeneration < statefulSet.Generation:
+		return fmt.Sprintf("generation %d not observed yet", statefulSet.Generation)
+	case status.UpdatedReplicas < replicas:
+		return fmt.Sprintf("%d of %d replicas updated", status.UpdatedReplicas, replicas)
+	case status.ReadyReplicas < replicas:
+		return fmt.Sprintf("%d of %d replicas ready", status.ReadyReplicas, replicas)
+	}
+	return ""
+}
+
+// podStates describes the pods matching the selector that are not ready: the containers that are waiting or
+// restarted, with the reason reported by the kubelet, and the latest warning events of the pod. Lookup failures are
+// ignored.
+func podStates(ctx context.Context, kubeClient *util.RetryableClient, namespace string, selector *v1.LabelSelector) []string {
+	states := []string{}
+	if selector == nil {
+		return states
+	}
+	labelSelector, err := v1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return states
+	}
+
+	pods := corev1.PodList{}
+	if err := kubeClient.Client.List(ctx, &pods, client.InNamespace(namespace), client.MatchingLabelsSelector{Selector: labelSelector}); err != nil {
+		return states
+	}
+	events := corev1.EventList{}
+	if err := kubeClient.Client.List(ctx, &events, client.InNamespace(namespace)); err != nil {
+		tflog.Debug(ctx, "unable to list events", map[string]any{"namespace": namespace, "error": err.Error()})
+	}
+	podEvents := map[string][]corev1.Event{}
+	for _, event := range events.Items {
+		if event.Type == corev1.EventTypeWarning && event.InvolvedObject.Kind == "Pod" {
+			podEvents[event.InvolvedObject.Name] = append(podEvents[event.InvolvedObject.Name], event)
+		}
+	}
+
+	sort.Slice(pods.Items, func(i, j int) bool { return pods.Items[i].Name < pods.Items[j].Name })
+	for _, pod := range pods.Items {
+		if podReady(pod) {
+			continue
+		}
+		states = append(states, fmt.Sprintf("pod %s: %s", pod.Name, pod.Status.Phase))
+		statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+		for _, cs := range statuses {
+			if cs.State.Waiting != nil && cs.State.Waiting.Reason != "" {
+				states = append(states, fmt.Sprintf("  container %s waiting: %s %s", cs.Name, cs.State.Waiting.Reason, cs.State.Waiting.Message))
+			}
+			if terminated := cs.LastTerminationState.Terminated; terminated != nil && cs.RestartCount > 0 {
+				states = append(states, fmt.Sprintf("  container %s restarted %d times, last terminated: %s (exit code %d) %s", cs.Name, cs.RestartCount, terminated.Reason, terminated.ExitCode, terminated.Message))
+			}
+		}
+
+		events := podEvents[pod.Name]
+		sort.Slice(events, func(i, j int) bool { return events[i].LastTimestamp.After(events[j].LastTimestamp.Time) })
+		for _, event := range events[:min(len(events), maxPodEvents)] {
+			states = append(states, fmt.Sprintf("  event %s: %s", event.Reason, event.Message))
+		}
+	}
+	return states
+}
+
+func podReady(pod corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
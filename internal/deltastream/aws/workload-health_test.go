@@ -0,0 +1,62 @@
+// Copyright (c) DeltaStream, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package aws
+
+import (
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+func TestDeploymentNotReadyReason(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  appsv1.DeploymentStatus
+		wantErr bool
+	}{
+		{name: "ready", status: appsv1.DeploymentStatus{ObservedGeneration: 2, UpdatedReplicas: 2, AvailableReplicas: 2}},
+		{name: "generation not observed", status: appsv1.DeploymentStatus{ObservedGeneration: 1, UpdatedReplicas: 2, AvailableReplicas: 2}, wantErr: true},
+		{name: "rolling out", status: appsv1.DeploymentStatus{ObservedGeneration: 2, UpdatedReplicas: 1, AvailableReplicas: 2}, wantErr: true},
+		{name: "crash looping", status: appsv1.DeploymentStatus{ObservedGeneration: 2, UpdatedReplicas: 2, AvailableReplicas: 1}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deployment := appsv1.Deployment{
+				ObjectMeta: v1.ObjectMeta{Name: "dp-manager", Generation: 2},
+				Spec:       appsv1.DeploymentSpec{Replicas: ptr.To[int32](2)},
+				Status:     tt.status,
+			}
+			if got := deploymentNotReadyReason(deployment); (got != "") != tt.wantErr {
+				t.Errorf("deploymentNotReadyReason() = %q, want not ready %v", got, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestStatefulSetNotReadyReason(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  appsv1.StatefulSetStatus
+		wantErr bool
+	}{
+		{name: "ready", status: appsv1.StatefulSetStatus{ObservedGeneration: 2, UpdatedReplicas: 3, ReadyReplicas: 3}},
+		{name: "generation not observed", status: appsv1.StatefulSetStatus{ObservedGeneration: 1, UpdatedReplicas: 3, ReadyReplicas: 3}, wantErr: true},
+		{name: "rolling out", status: appsv1.StatefulSetStatus{ObservedGeneration: 2, UpdatedReplicas: 2, ReadyReplicas: 3}, wantErr: true},
+		{name: "not ready", status: appsv1.StatefulSetStatus{ObservedGeneration: 2, UpdatedReplicas: 3, ReadyReplicas: 0}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			statefulSet := appsv1.StatefulSet{
+				ObjectMeta: v1.ObjectMeta{Name: "store-proxy", Generation: 2},
+				Spec:       appsv1.StatefulSetSpec{Replicas: ptr.To[int32](3)},
+				Status:     tt.status,
+			}
+			if got := statefulSetNotReadyReason(statefulSet); (got != "") != tt.wantErr {
+				t.Errorf("statefulSetNotReadyReason() = %q, want not ready %v", got, tt.wantErr)
+			}
+		})
+	}
+}
@@ -5,15 +5,22 @@ package provider
 
 import (
 	"context"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/float64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 
 	"github.com/deltastreaminc/terraform-provider-dataplane/internal/config"
 	"github.com/deltastreaminc/terraform-provider-dataplane/internal/deltastream/aws"
+	awsconfig "github.com/deltastreaminc/terraform-provider-dataplane/internal/deltastream/aws/config"
 )
 
 // Ensure ScaffoldingProvider satisfies various provider interfaces.
@@ -28,6 +35,20 @@ type DeltaStreamDataplaneProvider struct {
 
 // DeltaStreamDataplaneProviderModel describes the provider data model.
 type DeltaStreamDataplaneProviderModel struct {
+	HTTPProxy  types.String `tfsdk:"http_proxy"`
+	HTTPSProxy types.String `tfsdk:"https_proxy"`
+	NoProxy    types.String `tfsdk:"no_proxy"`
+
+	AwsMaxAttempts    types.Int64  `tfsdk:"aws_max_attempts"`
+	AwsRequestTimeout types.String `tfsdk:"aws_request_timeout"`
+
+	KubeMaxRetries types.Int64   `tfsdk:"kube_max_retries"`
+	KubeMaxBackoff types.String  `tfsdk:"kube_max_backoff"`
+	KubeQPS        types.Float64 `tfsdk:"kube_qps"`
+	KubeBurst      types.Int64   `tfsdk:"kube_burst"`
+
+	DefaultTags types.Map  `tfsdk:"default_tags"`
+	DryRun      types.Bool `tfsdk:"dry_run"`
 }
 
 func (p *DeltaStreamDataplaneProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
@@ -39,7 +60,59 @@ func (p *DeltaStreamDataplaneProvider) Schema(ctx context.Context, req provider.
 	resp.Schema = schema.Schema{
 		Description: "DeltaStream Dataplane provider",
 
-		Attributes: map[string]schema.Attribute{},
+		Attributes: map[string]schema.Attribute{
+			"http_proxy": schema.StringAttribute{
+				Description: "The proxy URL used for HTTP requests made by the AWS and kube clients.",
+				Optional:    true,
+			},
+			"https_proxy": schema.StringAttribute{
+				Description: "The proxy URL used for HTTPS requests made by the AWS and kube clients.",
+				Optional:    true,
+			},
+			"no_proxy": schema.StringAttribute{
+				Description: "Comma separated list of hosts that should bypass the proxy.",
+				Optional:    true,
+			},
+			"aws_max_attempts": schema.Int64Attribute{
+				Description: "The maximum number of attempts for AWS API requests. Throttled requests are retried with adaptive backoff (default: 10).",
+				Optional:    true,
+				Validators:  []validator.Int64{int64validator.AtLeast(1)},
+			},
+			"default_tags": schema.MapAttribute{
+				Description: "Tags applied to every AWS resource created by the provider. Resource level tags take precedence.",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"dry_run": schema.BoolAttribute{
+				Description: "Validate the configuration and apply manifests with server side dry run without changing AWS or the cluster. Creates and updates report the changes and then fail so that no state is recorded.",
+				Optional:    true,
+			},
+			"aws_request_timeout": schema.StringAttribute{
+				Description: "The time to wait for the response headers of a single AWS API request attempt. Object transfers are not cut off once the response started (default: 1m).",
+				Optional:    true,
+				Validators:  []validator.String{awsconfig.Duration()},
+			},
+			"kube_max_retries": schema.Int64Attribute{
+				Description: "The number of retries of failed in-cluster operations during install and destroy, with jittered exponential backoff starting at 5s (default: 5).",
+				Optional:    true,
+				Validators:  []validator.Int64{int64validator.AtLeast(1)},
+			},
+			"kube_max_backoff": schema.StringAttribute{
+				Description: "The maximum delay between retries of failed in-cluster operations (default: 1m).",
+				Optional:    true,
+				Validators:  []validator.String{awsconfig.Duration()},
+			},
+			"kube_qps": schema.Float64Attribute{
+				Description: "The maximum sustained rate of requests per second to the kube API server. Raise it for large installs (default: 50).",
+				Optional:    true,
+				Validators:  []validator.Float64{float64validator.AtLeast(1)},
+			},
+			"kube_burst": schema.Int64Attribute{
+				Description: "The maximum burst of requests to the kube API server above kube_qps (default: 100).",
+				Optional:    true,
+				Validators:  []validator.Int64{int64validator.AtLeast(1)},
+			},
+		},
 	}
 }
 func (p *DeltaStreamDataplaneProvider) Configure(ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
@@ -51,7 +124,53 @@ func (p *DeltaStreamDataplaneProvider) Configure(ctx context.Context, req provid
 		return
 	}
 
-	resp.ResourceData = &config.DataplaneResourceData{Version: p.version}
+	var awsRequestTimeout time.Duration
+	if !data.AwsRequestTimeout.IsNull() && !data.AwsRequestTimeout.IsUnknown() {
+		var err error
+		awsRequestTimeout, err = time.ParseDuration(data.AwsRequestTimeout.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("aws_request_timeout"), "Invalid AWS request timeout", err.Error())
+			return
+		}
+	}
+
+	var kubeMaxBackoff time.Duration
+	if !data.KubeMaxBackoff.IsNull() && !data.KubeMaxBackoff.IsUnknown() {
+		var err error
+		kubeMaxBackoff, err = time.ParseDuration(data.KubeMaxBackoff.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("kube_max_backoff"), "Invalid kube max backoff", err.Error())
+			return
+		}
+	}
+
+	defaultTags := map[string]string{}
+	if !data.DefaultTags.IsNull() && !data.DefaultTags.IsUnknown() {
+		resp.Diagnostics.Append(data.DefaultTags.ElementsAs(ctx, &defaultTags, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	providerData := &config.DataplaneResourceData{
+		Version:    p.version,
+		HTTPProxy:  data.HTTPProxy.ValueString(),
+		HTTPSProxy: data.HTTPSProxy.ValueString(),
+		NoProxy:    data.NoProxy.ValueString(),
+
+		AwsMaxAttempts:    int(data.AwsMaxAttempts.ValueInt64()),
+		AwsRequestTimeout: awsRequestTimeout,
+
+		KubeMaxRetries: int(data.KubeMaxRetries.ValueInt64()),
+		KubeMaxBackoff: kubeMaxBackoff,
+		KubeQPS:        data.KubeQPS.ValueFloat64(),
+		KubeBurst:      int(data.KubeBurst.ValueInt64()),
+
+		DefaultTags: defaultTags,
+		DryRun:      data.DryRun.ValueBool(),
+	}
+	resp.ResourceData = providerData
+	resp.DataSourceData = providerData
 }
 
 func (p *DeltaStreamDataplaneProvider) Resources(ctx context.Context) []func() resource.Resource {
@@ -61,11 +180,17 @@ func (p *DeltaStreamDataplaneProvider) Resources(ctx context.Context) []func() r
 }
 
 func (p *DeltaStreamDataplaneProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
-	return []func() datasource.DataSource{}
+	return []func() datasource.DataSource{
+		aws.NewAWSDataplaneVersionDataSource,
+		aws.NewAWSDataplaneClusterSettingsDataSource,
+		aws.NewAWSDataplaneImagesDataSource,
+	}
 }
 
 func (p *DeltaStreamDataplaneProvider) Functions(ctx context.Context) []func() function.Function {
-	return []func() function.Function{}
+	return []func() function.Function{
+		aws.NewClusterNameFunction,
+	}
 }
 
 func New(version string) func() provider.Provider {